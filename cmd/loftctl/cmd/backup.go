@@ -80,7 +80,7 @@ loft backup
 // Run executes the functionality
 func (cmd *BackupCmd) Run(cobraCmd *cobra.Command, args []string) error {
 	// first load the kube config
-	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), cmd.GlobalFlags.ConfigOverrides())
 
 	// load the raw config
 	kubeConfig, err := kubeClientConfig.ClientConfig()
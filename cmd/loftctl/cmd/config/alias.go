@@ -0,0 +1,40 @@
+package config
+
+import (
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+// NewAliasCmd creates a new cobra command
+func NewAliasCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	description := `
+#######################################################
+################## loft config alias ##################
+#######################################################
+Manages short aliases for cluster names. Aliases can be
+used with every --cluster flag.
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################ devspace config alias ################
+#######################################################
+Manages short aliases for cluster names. Aliases can be
+used with every --cluster flag.
+#######################################################
+	`
+	}
+	aliasCmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manages cluster aliases",
+		Long:  description,
+		Args:  cobra.NoArgs,
+	}
+
+	aliasCmd.AddCommand(NewAliasSetCmd(globalFlags))
+	aliasCmd.AddCommand(NewAliasListCmd(globalFlags))
+	aliasCmd.AddCommand(NewAliasRemoveCmd(globalFlags))
+	return aliasCmd
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+// AliasListCmd holds the cmd flags
+type AliasListCmd struct {
+	*flags.GlobalFlags
+
+	log log.Logger
+}
+
+// NewAliasListCmd creates a new command
+func NewAliasListCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &AliasListCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+############### loft config alias list ################
+#######################################################
+Lists the stored cluster aliases
+
+Example:
+loft config alias list
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############# devspace config alias list ##############
+#######################################################
+Lists the stored cluster aliases
+
+Example:
+devspace config alias list
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the cluster aliases",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	return c
+}
+
+// Run executes the command
+func (cmd *AliasListCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	aliases := baseClient.Config().ClusterAliases
+	names := []string{}
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := [][]string{}
+	for _, name := range names {
+		values = append(values, []string{name, aliases[name]})
+	}
+
+	log.PrintTable(cmd.log, []string{"Alias", "Cluster"}, values)
+	return nil
+}
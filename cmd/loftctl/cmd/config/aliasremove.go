@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// AliasRemoveCmd holds the cmd flags
+type AliasRemoveCmd struct {
+	*flags.GlobalFlags
+
+	log log.Logger
+}
+
+// NewAliasRemoveCmd creates a new command
+func NewAliasRemoveCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &AliasRemoveCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+############## loft config alias remove ###############
+#######################################################
+Removes a cluster alias
+
+Example:
+loft config alias remove prod
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############ devspace config alias remove #############
+#######################################################
+Removes a cluster alias
+
+Example:
+devspace config alias remove prod
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "remove",
+		Short: "Removes a cluster alias",
+		Long:  description,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	return c
+}
+
+// Run executes the command
+func (cmd *AliasRemoveCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	config := baseClient.Config()
+	if _, ok := config.ClusterAliases[args[0]]; !ok {
+		return fmt.Errorf("cluster alias %s does not exist", args[0])
+	}
+
+	delete(config.ClusterAliases, args[0])
+	err = baseClient.Save()
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully removed alias %s", ansi.Color(args[0], "white+b"))
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// AliasSetCmd holds the cmd flags
+type AliasSetCmd struct {
+	*flags.GlobalFlags
+
+	log log.Logger
+}
+
+// NewAliasSetCmd creates a new command
+func NewAliasSetCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &AliasSetCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+################ loft config alias set ################
+#######################################################
+Stores an alias for a cluster name
+
+Example:
+loft config alias set prod loft-cluster-prod-eu-west
+loft create space myspace --cluster prod
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############## devspace config alias set ##############
+#######################################################
+Stores an alias for a cluster name
+
+Example:
+devspace config alias set prod loft-cluster-prod-eu-west
+devspace create space myspace --cluster prod
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "set",
+		Short: "Stores an alias for a cluster name",
+		Long:  description,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	return c
+}
+
+// Run executes the command
+func (cmd *AliasSetCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	config := baseClient.Config()
+	if config.ClusterAliases == nil {
+		config.ClusterAliases = map[string]string{}
+	}
+
+	config.ClusterAliases[args[0]] = args[1]
+	err = baseClient.Save()
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully stored alias %s for cluster %s", ansi.Color(args[0], "white+b"), ansi.Color(args[1], "white+b"))
+	return nil
+}
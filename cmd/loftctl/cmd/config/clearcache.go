@@ -0,0 +1,94 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ClearCacheCmd holds the cmd flags
+type ClearCacheCmd struct {
+	*flags.GlobalFlags
+
+	log log.Logger
+}
+
+// NewClearCacheCmd creates a new command
+func NewClearCacheCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ClearCacheCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+############### loft config clear-cache ###############
+#######################################################
+Removes the files loft caches next to the loft config,
+e.g. the latest version found by the version check.
+The config itself is not touched.
+
+Example:
+loft config clear-cache
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############# devspace config clear-cache #############
+#######################################################
+Removes the files loft caches next to the loft config,
+e.g. the latest version found by the version check.
+The config itself is not touched.
+
+Example:
+devspace config clear-cache
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "clear-cache",
+		Short: "Removes the cached loft files",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	return c
+}
+
+// Run executes the command
+func (cmd *ClearCacheCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	cacheDir := client.CacheDir(cmd.Config)
+	files, err := ioutil.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			cmd.log.Info("Nothing to clear, the cache is empty")
+			return nil
+		}
+
+		return errors.Wrap(err, "read cache dir")
+	}
+
+	for _, file := range files {
+		err = os.RemoveAll(filepath.Join(cacheDir, file.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "remove %s", file.Name())
+		}
+
+		cmd.log.Infof("Removed %s", ansi.Color(file.Name(), "white+b"))
+	}
+
+	cmd.log.Donef("Successfully cleared the cache at %s", cacheDir)
+	return nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates a new cobra command
+func NewConfigCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	description := `
+#######################################################
+##################### loft config #####################
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################### devspace config ###################
+#######################################################
+	`
+	}
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manages the loft client config",
+		Long:  description,
+		Args:  cobra.NoArgs,
+	}
+
+	configCmd.AddCommand(NewViewCmd(globalFlags))
+	configCmd.AddCommand(NewSetContextCmd(globalFlags))
+	configCmd.AddCommand(NewUseContextCmd(globalFlags))
+	configCmd.AddCommand(NewAliasCmd(globalFlags))
+	configCmd.AddCommand(NewClearCacheCmd(globalFlags))
+	return configCmd
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// SetContextCmd holds the cmd flags
+type SetContextCmd struct {
+	*flags.GlobalFlags
+
+	Server    string
+	AccessKey string
+	Insecure  bool
+
+	log log.Logger
+}
+
+// NewSetContextCmd creates a new command
+func NewSetContextCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &SetContextCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+############### loft config set-context ###############
+#######################################################
+Stores a named loft context. If no server is specified,
+the loft instance you are currently logged into is used.
+
+Example:
+loft config set-context staging
+loft config set-context prod --server https://loft.my-domain.tld --access-key myaccesskey
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############# devspace config set-context #############
+#######################################################
+Stores a named loft context. If no server is specified,
+the loft instance you are currently logged into is used.
+
+Example:
+devspace config set-context staging
+devspace config set-context prod --server https://loft.my-domain.tld --access-key myaccesskey
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "set-context",
+		Short: "Stores a named loft context",
+		Long:  description,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Server, "server", "", "The loft server to use for the context. Defaults to the loft server you are currently logged into")
+	c.Flags().StringVar(&cmd.AccessKey, "access-key", "", "The access key to use for the context")
+	c.Flags().BoolVar(&cmd.Insecure, "insecure", false, "Allow an insecure loft instance for the context")
+	return c
+}
+
+// Run executes the command
+func (cmd *SetContextCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	config := baseClient.Config()
+	context := &client.Context{
+		Host:      config.Host,
+		Insecure:  config.Insecure,
+		AccessKey: config.AccessKey,
+	}
+	if cmd.Server != "" {
+		server := strings.TrimSuffix(cmd.Server, "/")
+		if strings.HasPrefix(server, "http") == false {
+			server = "https://" + server
+		}
+
+		context = &client.Context{
+			Host:      server,
+			Insecure:  cmd.Insecure,
+			AccessKey: cmd.AccessKey,
+		}
+	}
+	if context.Host == "" {
+		return fmt.Errorf("not logged in, please specify --server or run 'loft login [loft-url]' first")
+	}
+
+	err = baseClient.SetContext(args[0], context)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully stored loft context %s for %s", ansi.Color(args[0], "white+b"), ansi.Color(context.Host, "white+b"))
+	return nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/survey"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// UseContextCmd holds the cmd flags
+type UseContextCmd struct {
+	*flags.GlobalFlags
+
+	log log.Logger
+}
+
+// NewUseContextCmd creates a new command
+func NewUseContextCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &UseContextCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+############### loft config use-context ###############
+#######################################################
+Switches the loft instance loft commands talk to
+
+Example:
+loft config use-context
+loft config use-context prod
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############# devspace config use-context #############
+#######################################################
+Switches the loft instance loft commands talk to
+
+Example:
+devspace config use-context
+devspace config use-context prod
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "use-context",
+		Short: "Switches the active loft context",
+		Long:  description,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	return c
+}
+
+// Run executes the command
+func (cmd *UseContextCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	contextName := ""
+	if len(args) > 0 {
+		contextName = args[0]
+	} else {
+		contextNames := []string{}
+		for name := range baseClient.Config().Contexts {
+			contextNames = append(contextNames, name)
+		}
+		if len(contextNames) == 0 {
+			return fmt.Errorf("no loft contexts found, please run 'loft config set-context' first")
+		}
+		sort.Strings(contextNames)
+
+		defaultValue := contextNames[0]
+		if baseClient.Config().Contexts[baseClient.Config().CurrentContext] != nil {
+			defaultValue = baseClient.Config().CurrentContext
+		}
+
+		contextName, err = cmd.log.Question(&survey.QuestionOptions{
+			Question:     "Please choose a loft context to use",
+			DefaultValue: defaultValue,
+			Options:      contextNames,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = baseClient.UseContext(contextName)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully switched to loft context %s (%s)", ansi.Color(contextName, "white+b"), ansi.Color(baseClient.Config().Host, "white+b"))
+	return nil
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+const redacted = "REDACTED"
+
+// ViewCmd holds the cmd flags
+type ViewCmd struct {
+	*flags.GlobalFlags
+
+	Output string
+
+	log log.Logger
+}
+
+// ViewConfig is the non secret view of the loft client config
+type ViewConfig struct {
+	ConfigPath         string `json:"configPath"`
+	CurrentContext     string `json:"currentContext,omitempty"`
+	Server             string `json:"server,omitempty"`
+	Insecure           bool   `json:"insecure,omitempty"`
+	User               string `json:"user,omitempty"`
+	Team               string `json:"team,omitempty"`
+	LastInstallContext string `json:"lastInstallContext,omitempty"`
+	AccessKey          string `json:"accessKey,omitempty"`
+}
+
+// NewViewCmd creates a new command
+func NewViewCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ViewCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+################## loft config view ###################
+#######################################################
+Shows the loft client config that is currently used.
+Tokens and access keys are redacted.
+
+Example:
+loft config view
+loft config view -o yaml
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################ devspace config view #################
+#######################################################
+Shows the loft client config that is currently used.
+Tokens and access keys are redacted.
+
+Example:
+devspace config view
+devspace config view -o yaml
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "view",
+		Short: "Shows the loft client config",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVarP(&cmd.Output, "output", "o", "", "The output format to use. Valid options are: yaml")
+	return c
+}
+
+// Run executes the command
+func (cmd *ViewCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	if cmd.Output != "" && cmd.Output != "yaml" {
+		return fmt.Errorf("unsupported output format %s, valid options are: yaml", cmd.Output)
+	}
+
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	config := baseClient.Config()
+	viewConfig := &ViewConfig{
+		ConfigPath:         cmd.Config,
+		CurrentContext:     config.CurrentContext,
+		Server:             config.Host,
+		Insecure:           config.Insecure,
+		LastInstallContext: config.LastInstallContext,
+	}
+	if config.AccessKey != "" {
+		viewConfig.AccessKey = redacted
+	}
+
+	// try to retrieve the current user
+	if config.Host != "" && config.AccessKey != "" {
+		managementClient, err := baseClient.Management()
+		if err == nil {
+			viewConfig.User, viewConfig.Team, err = helper.GetCurrentUser(context.TODO(), managementClient)
+		}
+		if err != nil {
+			cmd.log.Warnf("Couldn't retrieve the current user: %v", err)
+		}
+	}
+
+	if cmd.Output == "yaml" {
+		out, err := yaml.Marshal(viewConfig)
+		if err != nil {
+			return err
+		}
+
+		_, err = cmd.log.Write(out)
+		return err
+	}
+
+	values := [][]string{
+		{"Config Path", viewConfig.ConfigPath},
+		{"Current Context", viewConfig.CurrentContext},
+		{"Server", viewConfig.Server},
+		{"Insecure", strconv.FormatBool(viewConfig.Insecure)},
+		{"User", viewConfig.User},
+		{"Team", viewConfig.Team},
+		{"Last Install Context", viewConfig.LastInstallContext},
+		{"Access Key", viewConfig.AccessKey},
+	}
+
+	log.PrintTable(cmd.log, []string{"Key", "Value"}, values)
+	return nil
+}
@@ -0,0 +1,202 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	managementv1 "github.com/loft-sh/api/pkg/apis/management/v1"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/clihelper"
+	"github.com/loft-sh/loftctl/pkg/kubeconfig"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"strings"
+)
+
+// ClusterCmd holds the cmd flags
+type ClusterCmd struct {
+	*flags.GlobalFlags
+
+	Context        string
+	Namespace      string
+	ServiceAccount string
+	Version        string
+	ChartName      string
+	ChartRepo      string
+
+	log log.Logger
+}
+
+// NewClusterCmd creates a new command
+func NewClusterCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ClusterCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################ loft connect cluster #################
+#######################################################
+Installs the loft agent into the cluster of the given
+kube context and connects the cluster to the loft
+instance you are logged into.
+
+Example:
+loft connect cluster mycluster
+loft connect cluster mycluster --context my-kube-context
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############## devspace connect cluster ###############
+#######################################################
+Installs the loft agent into the cluster of the given
+kube context and connects the cluster to the loft
+instance you are logged into.
+
+Example:
+devspace connect cluster mycluster
+devspace connect cluster mycluster --context my-kube-context
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "cluster",
+		Short: "Connects a cluster to loft",
+		Long:  description,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Context, "context", "", "The kube context of the cluster to connect. Defaults to the current kube context")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", "The namespace to install the loft agent into")
+	c.Flags().StringVar(&cmd.ServiceAccount, "service-account", "loft-admin", "The service account loft uses to access the cluster")
+	c.Flags().StringVar(&cmd.Version, "version", "", "The loft agent version to install. Defaults to the version of the loft server")
+	c.Flags().StringVar(&cmd.ChartName, "chart", clihelper.DefaultChartName, "The loft chart to install the agent from. Can also be a path to a local chart")
+	c.Flags().StringVar(&cmd.ChartRepo, "repo", clihelper.DefaultChartRepo, "The helm repository to install the loft chart from")
+	return c
+}
+
+// Run executes the command
+func (cmd *ClusterCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	clusterName := args[0]
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	managementClient, err := baseClient.Management()
+	if err != nil {
+		return err
+	}
+
+	user, _, err := helper.GetCurrentUser(context.TODO(), managementClient)
+	if err != nil {
+		return err
+	} else if user == "" {
+		return fmt.Errorf("connecting a cluster requires a loft user, please log in as a user instead of a team")
+	}
+
+	// load the kube context of the cluster to connect
+	kubeConfigLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{CurrentContext: cmd.Context})
+	if cmd.Context == "" {
+		rawConfig, err := kubeConfigLoader.RawConfig()
+		if err != nil {
+			return err
+		}
+
+		cmd.Context = rawConfig.CurrentContext
+	}
+
+	restConfig, err := kubeConfigLoader.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	restConfig.UserAgent = upgrade.UserAgent()
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "create kube client")
+	}
+
+	err = cmd.resolveAgentVersion(baseClient.Config().Host)
+	if err != nil {
+		return err
+	}
+
+	// install the agent
+	err = clihelper.InstallLoftAgent(cmd.ChartName, cmd.ChartRepo, cmd.Context, cmd.Namespace, cmd.Version, nil, cmd.log)
+	if err != nil {
+		return err
+	}
+
+	// create the kube config loft uses to access the cluster
+	token, err := clihelper.CreateAdminServiceAccountToken(kubeClient, cmd.Namespace, cmd.ServiceAccount)
+	if err != nil {
+		return errors.Wrap(err, "create service account token")
+	}
+
+	clusterKubeConfig := &bytes.Buffer{}
+	err = kubeconfig.PrintTokenKubeConfigTo(restConfig, token, clusterKubeConfig)
+	if err != nil {
+		return err
+	}
+
+	// register the cluster in loft
+	cmd.log.StartWait("Connecting cluster " + clusterName + " to loft...")
+	_, err = managementClient.Loft().ManagementV1().ClusterConnects().Create(context.TODO(), &managementv1.ClusterConnect{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Spec: managementv1.ClusterConnectSpec{
+			Config:    clusterKubeConfig.String(),
+			AdminUser: user,
+			ClusterTemplate: managementv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: clusterName,
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	cmd.log.StopWait()
+	if err != nil {
+		return errors.Wrap(err, "connect cluster")
+	}
+
+	cmd.log.Donef("Successfully connected cluster %s to loft", ansi.Color(clusterName, "white+b"))
+	return nil
+}
+
+// resolveAgentVersion defaults the agent version to the version of the loft server and warns if a
+// different version was requested, because agents that differ from the server can misbehave
+func (cmd *ClusterCmd) resolveAgentVersion(loftHost string) error {
+	serverVersion, err := clihelper.GetLoftVersion(strings.TrimPrefix(strings.TrimPrefix(loftHost, "https://"), "http://"))
+	if err != nil {
+		return errors.Wrap(err, "retrieve loft server version")
+	}
+	serverVersion = strings.TrimPrefix(serverVersion, "v")
+
+	if serverVersion == "" {
+		if cmd.Version == "" {
+			cmd.log.Warnf("Couldn't retrieve the version of the loft server at %s, installing the latest loft agent", loftHost)
+		}
+	} else if cmd.Version == "" {
+		cmd.Version = serverVersion
+		cmd.log.Infof("Installing loft agent version %s to match the loft server", cmd.Version)
+	} else if strings.TrimPrefix(cmd.Version, "v") != serverVersion {
+		cmd.log.Warnf("The loft agent version %s differs from the loft server version %s, which can lead to unexpected behaviour", cmd.Version, serverVersion)
+	}
+
+	return nil
+}
@@ -27,6 +27,7 @@ func NewConnectCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 		Args:  cobra.NoArgs,
 	}
 
+	c.AddCommand(NewClusterCmd(globalFlags))
 	c.AddCommand(NewVirtualClusterCmd(globalFlags))
 	return c
 }
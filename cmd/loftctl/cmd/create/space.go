@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"time"
 
 	"github.com/loft-sh/agentapi/pkg/apis/kiosk/config/v1alpha1"
 	tenancyv1alpha1 "github.com/loft-sh/agentapi/pkg/apis/kiosk/tenancy/v1alpha1"
@@ -53,6 +54,7 @@ type SpaceCmd struct {
 	SwitchContext                bool
 	DisableDirectClusterEndpoint bool
 	Template                     string
+	WaitForPhase                 string
 
 	Log log.Logger
 }
@@ -74,6 +76,7 @@ Example:
 loft create space myspace
 loft create space myspace --cluster mycluster
 loft create space myspace --cluster mycluster --account myaccount
+loft create space myspace --wait-for-phase Active
 #######################################################
 	`
 	if upgrade.IsPlugin == "true" {
@@ -88,6 +91,7 @@ Example:
 devspace create space myspace
 devspace create space myspace --cluster mycluster
 devspace create space myspace --cluster mycluster --account myaccount
+devspace create space myspace --wait-for-phase Active
 #######################################################
 	`
 	}
@@ -111,6 +115,7 @@ devspace create space myspace --cluster mycluster --account myaccount
 	c.Flags().BoolVar(&cmd.CreateContext, "create-context", true, "If loft should create a kube context for the space")
 	c.Flags().BoolVar(&cmd.SwitchContext, "switch-context", true, "If loft should switch the current context to the new context")
 	c.Flags().StringVar(&cmd.Template, "template", "", "The space template to use")
+	c.Flags().StringVar(&cmd.WaitForPhase, "wait-for-phase", "", "If set, waits until the space has reached this status phase, e.g. Active")
 	c.Flags().BoolVar(&cmd.DisableDirectClusterEndpoint, "disable-direct-cluster-endpoint", false, "When enabled does not use an available direct cluster endpoint to connect to the space")
 	return c
 }
@@ -229,6 +234,13 @@ func (cmd *SpaceCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmd.WaitForPhase != "" {
+		err = helper.WaitForSpacePhase(clusterClient, spaceName, cmd.WaitForPhase, time.Minute*5, cmd.Log)
+		if err != nil {
+			return err
+		}
+	}
+
 	cmd.Log.Donef("Successfully created the space %s in cluster %s", ansi.Color(spaceName, "white+b"), ansi.Color(clusterName, "white+b"))
 
 	// should we create a kube context for the space
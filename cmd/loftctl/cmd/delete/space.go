@@ -2,6 +2,7 @@ package delete
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
@@ -11,6 +12,7 @@ import (
 	"github.com/loft-sh/loftctl/pkg/kubeconfig"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
 	"github.com/mgutz/ansi"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -96,6 +98,13 @@ func (cmd *SpaceCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	confirmed, err := util.ConfirmDestructive("Do you really want to delete space "+spaceName+" in cluster "+clusterName+" including everything in it?", cmd.AssumeYes, cmd.Log)
+	if err != nil {
+		return err
+	} else if confirmed == false {
+		return fmt.Errorf("aborted deleting space %s, run with --yes to skip the confirmation", spaceName)
+	}
+
 	clusterClient, err := baseClient.Cluster(clusterName)
 	if err != nil {
 		return err
@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/clihelper"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+	"time"
+)
+
+// DoctorCmd holds the cmd flags
+type DoctorCmd struct {
+	*flags.GlobalFlags
+
+	Context   string
+	Namespace string
+
+	KubeClient kubernetes.Interface
+	RestConfig *rest.Config
+	Log        log.Logger
+}
+
+// doctorFinding is the result of a single doctor check
+type doctorFinding struct {
+	Check       string
+	Ok          bool
+	Details     string
+	Remediation string
+}
+
+// NewDoctorCmd creates a new command
+func NewDoctorCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DoctorCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+##################### loft doctor #####################
+#######################################################
+Doctor inspects an existing loft installation for
+common problems and prints hints how to fix them
+
+Example:
+loft doctor
+loft doctor --context mycontext --namespace loft
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################### devspace doctor ###################
+#######################################################
+Doctor inspects an existing loft installation for
+common problems and prints hints how to fix them
+
+Example:
+devspace doctor
+devspace doctor --context mycontext --namespace loft
+#######################################################
+	`
+	}
+
+	c := &cobra.Command{
+		Use:   "doctor",
+		Short: "Inspects a loft installation for common problems",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run()
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Context, "context", "", "The kube context to use for the inspection")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", "The namespace loft is installed in")
+	return c
+}
+
+// Run executes the command logic
+func (cmd *DoctorCmd) Run() error {
+	overrides := cmd.GlobalFlags.ConfigOverrides()
+	overrides.CurrentContext = cmd.Context
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), overrides)
+	restConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	restConfig.UserAgent = upgrade.UserAgent()
+	cmd.RestConfig = kube.WithRequestTracing(restConfig, cmd.Log)
+	cmd.KubeClient, err = kubernetes.NewForConfig(cmd.RestConfig)
+	if err != nil {
+		return err
+	}
+
+	isInstalled, err := clihelper.IsLoftAlreadyInstalled(cmd.KubeClient, cmd.Namespace)
+	if err != nil {
+		return err
+	} else if isInstalled == false {
+		return fmt.Errorf("couldn't find a loft installation in namespace %s, please make sure you use the correct --context and --namespace", cmd.Namespace)
+	}
+
+	cmd.Log.StartWait("Inspecting loft installation...")
+	findings := []doctorFinding{
+		cmd.checkLoftPod(),
+		cmd.checkAPIService(),
+		cmd.checkWebhook(),
+	}
+	findings = append(findings, cmd.checkIngressAndCertificate()...)
+	cmd.Log.StopWait()
+
+	failed := 0
+	values := [][]string{}
+	for _, finding := range findings {
+		status := "OK"
+		if finding.Ok == false {
+			status = "FAILED"
+			failed++
+		}
+
+		values = append(values, []string{finding.Check, status, finding.Details})
+	}
+
+	log.PrintTable(cmd.Log, []string{"Check", "Status", "Details"}, values)
+	if failed == 0 {
+		cmd.Log.Donef("No problems found in the loft installation in namespace %s", cmd.Namespace)
+		return nil
+	}
+
+	for _, finding := range findings {
+		if finding.Ok == false && finding.Remediation != "" {
+			cmd.Log.Warnf("%s: %s", finding.Check, finding.Remediation)
+		}
+	}
+
+	return fmt.Errorf("found %d problem(s) in the loft installation", failed)
+}
+
+func (cmd *DoctorCmd) checkLoftPod() doctorFinding {
+	finding := doctorFinding{
+		Check:       "Loft pod",
+		Remediation: fmt.Sprintf("Check the loft logs with 'kubectl logs -n %s -l app=loft' and the pod events with 'kubectl describe pods -n %s -l app=loft'", cmd.Namespace, cmd.Namespace),
+	}
+
+	pods, err := cmd.KubeClient.CoreV1().Pods(cmd.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: "app=loft"})
+	if err != nil {
+		finding.Details = err.Error()
+		return finding
+	} else if len(pods.Items) == 0 {
+		finding.Details = "no loft pod found"
+		return finding
+	}
+
+	for _, pod := range pods.Items {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				finding.Ok = true
+				finding.Details = fmt.Sprintf("pod %s is ready", pod.Name)
+				return finding
+			}
+		}
+	}
+
+	finding.Details = fmt.Sprintf("pod %s is not ready (phase %s)", pods.Items[0].Name, pods.Items[0].Status.Phase)
+	return finding
+}
+
+func (cmd *DoctorCmd) checkAPIService() doctorFinding {
+	finding := doctorFinding{
+		Check:       "APIService",
+		Remediation: fmt.Sprintf("Check the status with 'kubectl get apiservice %s -o yaml' and make sure the loft pod is ready", clihelper.LoftAPIServiceName),
+	}
+
+	apiRegistrationClient, err := clientset.NewForConfig(cmd.RestConfig)
+	if err != nil {
+		finding.Details = err.Error()
+		return finding
+	}
+
+	apiService, err := apiRegistrationClient.ApiregistrationV1().APIServices().Get(context.TODO(), clihelper.LoftAPIServiceName, metav1.GetOptions{})
+	if err != nil {
+		finding.Details = err.Error()
+		return finding
+	}
+
+	for _, condition := range apiService.Status.Conditions {
+		if condition.Type == apiregistrationv1.Available {
+			if condition.Status == apiregistrationv1.ConditionTrue {
+				finding.Ok = true
+				finding.Details = fmt.Sprintf("%s is available", clihelper.LoftAPIServiceName)
+			} else {
+				finding.Details = fmt.Sprintf("%s is not available: %s", clihelper.LoftAPIServiceName, condition.Message)
+			}
+
+			return finding
+		}
+	}
+
+	finding.Details = fmt.Sprintf("%s has no Available condition", clihelper.LoftAPIServiceName)
+	return finding
+}
+
+func (cmd *DoctorCmd) checkWebhook() doctorFinding {
+	finding := doctorFinding{
+		Check:       "Validating webhook",
+		Remediation: "Make sure the loft pod is ready and the webhook service has endpoints, otherwise requests to the cluster might be rejected",
+	}
+
+	webhookConfig, err := cmd.KubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), "loft", metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			finding.Ok = true
+			finding.Details = "no validating webhook configured"
+			return finding
+		}
+
+		finding.Details = err.Error()
+		return finding
+	}
+
+	for _, webhook := range webhookConfig.Webhooks {
+		if webhook.ClientConfig.Service == nil {
+			continue
+		}
+
+		service := webhook.ClientConfig.Service
+		endpoints, err := cmd.KubeClient.CoreV1().Endpoints(service.Namespace).Get(context.TODO(), service.Name, metav1.GetOptions{})
+		if err != nil {
+			finding.Details = fmt.Sprintf("webhook service %s/%s: %v", service.Namespace, service.Name, err)
+			return finding
+		}
+
+		hasAddress := false
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				hasAddress = true
+				break
+			}
+		}
+		if hasAddress == false {
+			finding.Details = fmt.Sprintf("webhook service %s/%s has no ready endpoints", service.Namespace, service.Name)
+			return finding
+		}
+	}
+
+	finding.Ok = true
+	finding.Details = "webhook service has ready endpoints"
+	return finding
+}
+
+func (cmd *DoctorCmd) checkIngressAndCertificate() []doctorFinding {
+	host, err := clihelper.GetLoftIngressHost(cmd.KubeClient, cmd.Namespace)
+	if err != nil {
+		// loft was installed without an ingress
+		return nil
+	}
+
+	ingressFinding := doctorFinding{
+		Check:       "Ingress",
+		Remediation: "Make sure an ingress controller is installed and running in the cluster",
+	}
+
+	addresses := 0
+	ingress, err := cmd.KubeClient.NetworkingV1().Ingresses(cmd.Namespace).Get(context.TODO(), "loft-ingress", metav1.GetOptions{})
+	if err == nil {
+		addresses = len(ingress.Status.LoadBalancer.Ingress)
+	} else {
+		betaIngress, betaErr := cmd.KubeClient.NetworkingV1beta1().Ingresses(cmd.Namespace).Get(context.TODO(), "loft-ingress", metav1.GetOptions{})
+		if betaErr == nil {
+			addresses = len(betaIngress.Status.LoadBalancer.Ingress)
+		}
+		err = betaErr
+	}
+	if err != nil {
+		ingressFinding.Details = err.Error()
+	} else if addresses == 0 {
+		ingressFinding.Details = "ingress loft-ingress has no address assigned"
+	} else {
+		ingressFinding.Ok = true
+		ingressFinding.Details = fmt.Sprintf("ingress loft-ingress for host %s has an address", host)
+	}
+
+	certificateFinding := doctorFinding{
+		Check:       "Certificate",
+		Remediation: "Follow this guide to add a valid certificate: https://loft.sh/docs/administration/ssl",
+	}
+
+	certificate, err := clihelper.GetLoftCertificate(host)
+	if err != nil {
+		certificateFinding.Details = fmt.Sprintf("couldn't inspect the certificate of https://%s: %v", host, err)
+	} else if certificate.NotAfter.Before(time.Now()) {
+		certificateFinding.Details = fmt.Sprintf("certificate of https://%s has expired on %s", host, certificate.NotAfter.Format(time.RFC1123))
+	} else {
+		certificateFinding.Ok = true
+		certificateFinding.Details = fmt.Sprintf("certificate of https://%s is valid until %s", host, certificate.NotAfter.Format(time.RFC1123))
+	}
+
+	return []doctorFinding{ingressFinding, certificateFinding}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventsCmd holds the cmd flags
+type EventsCmd struct {
+	*flags.GlobalFlags
+
+	Cluster string
+	Watch   bool
+
+	Log log.Logger
+}
+
+// NewEventsCmd creates a new command
+func NewEventsCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &EventsCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+##################### loft events #####################
+#######################################################
+Shows the recent kubernetes events of a space
+
+Example:
+loft events myspace
+loft events myspace --cluster mycluster
+loft events myspace --watch
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################### devspace events ###################
+#######################################################
+Shows the recent kubernetes events of a space
+
+Example:
+devspace events myspace
+devspace events myspace --cluster mycluster
+devspace events myspace --watch
+#######################################################
+	`
+	}
+
+	c := &cobra.Command{
+		Use:   "events",
+		Short: "Shows the events of a space",
+		Long:  description,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster to use")
+	c.Flags().BoolVarP(&cmd.Watch, "watch", "w", false, "If enabled, keeps printing new events of the space")
+	return c
+}
+
+// Run executes the command
+func (cmd *EventsCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	spaceName := ""
+	if len(args) > 0 {
+		spaceName = args[0]
+	}
+
+	spaceName, clusterName, err := helper.SelectSpaceAndClusterName(baseClient, spaceName, cmd.Cluster, cmd.Log)
+	if err != nil {
+		return err
+	}
+
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	events, err := clusterClient.CoreV1().Events(spaceName).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).Before(eventTime(&events.Items[j]))
+	})
+
+	values := [][]string{}
+	for i := range events.Items {
+		values = append(values, eventRow(&events.Items[i]))
+	}
+
+	log.PrintTable(cmd.Log, []string{"Last Seen", "Type", "Reason", "Object", "Message"}, values)
+	if cmd.Watch == false {
+		return nil
+	}
+
+	watcher, err := clusterClient.CoreV1().Events(spaceName).Watch(context.TODO(), metav1.ListOptions{ResourceVersion: events.ResourceVersion})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for watchEvent := range watcher.ResultChan() {
+		if watchEvent.Type == watch.Error {
+			return fmt.Errorf("watch events of space %s: %v", spaceName, watchEvent.Object)
+		} else if watchEvent.Type != watch.Added && watchEvent.Type != watch.Modified {
+			continue
+		}
+
+		event, ok := watchEvent.Object.(*corev1.Event)
+		if !ok {
+			continue
+		}
+
+		cmd.Log.WriteString(strings.Join(eventRow(event), "\t") + "\n")
+	}
+
+	return nil
+}
+
+// eventTime returns the time the event was seen last
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	} else if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
+
+func eventRow(event *corev1.Event) []string {
+	return []string{
+		duration.HumanDuration(time.Now().Sub(eventTime(event))),
+		event.Type,
+		event.Reason,
+		strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name,
+		strings.TrimSpace(event.Message),
+	}
+}
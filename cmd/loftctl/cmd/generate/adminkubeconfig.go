@@ -1,23 +1,16 @@
 package generate
 
 import (
-	"context"
-	"fmt"
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/clihelper"
 	"github.com/loft-sh/loftctl/pkg/kubeconfig"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"time"
 )
 
 // AdminKubeConfigCmd holds the cmd flags
@@ -89,76 +82,11 @@ func (cmd *AdminKubeConfigCmd) Run(c *rest.Config, cobraCmd *cobra.Command, args
 		return errors.Wrap(err, "create kube client")
 	}
 
-	// make sure namespace exists
-	_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
-		ObjectMeta: v1.ObjectMeta{
-			Name: cmd.Namespace,
-		},
-	}, v1.CreateOptions{})
-	if err != nil {
-		if kerrors.IsAlreadyExists(err) == false {
-			return err
-		}
-	}
-
-	// create service account
-	_, err = client.CoreV1().ServiceAccounts(cmd.Namespace).Create(context.TODO(), &corev1.ServiceAccount{
-		ObjectMeta: v1.ObjectMeta{
-			Name: cmd.ServiceAccount,
-		},
-	}, v1.CreateOptions{})
-	if err != nil {
-		if kerrors.IsAlreadyExists(err) == false {
-			return err
-		}
-	}
-
-	// create clusterrolebinding
-	client.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
-		ObjectMeta: v1.ObjectMeta{
-			Name: cmd.ServiceAccount + "-binding",
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      "ServiceAccount",
-				Name:      cmd.ServiceAccount,
-				Namespace: cmd.Namespace,
-			},
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: rbacv1.GroupName,
-			Kind:     "ClusterRole",
-			Name:     "cluster-admin",
-		},
-	}, v1.CreateOptions{})
-
-	// wait for secret
-	token := []byte{}
-	err = wait.Poll(time.Millisecond*250, time.Minute*2, func() (bool, error) {
-		serviceAccount, err := client.CoreV1().ServiceAccounts(cmd.Namespace).Get(context.TODO(), cmd.ServiceAccount, v1.GetOptions{})
-		if err != nil {
-			return false, errors.Wrap(err, "retrieve service account")
-		} else if len(serviceAccount.Secrets) == 0 {
-			return false, nil
-		}
-
-		secret, err := client.CoreV1().Secrets(cmd.Namespace).Get(context.TODO(), serviceAccount.Secrets[0].Name, v1.GetOptions{})
-		if err != nil {
-			return false, errors.Wrap(err, "get service account secret")
-		}
-
-		ok := false
-		token, ok = secret.Data["token"]
-		if !ok {
-			return false, fmt.Errorf("service account secret has unexpected contents")
-		}
-
-		return true, nil
-	})
+	token, err := clihelper.CreateAdminServiceAccountToken(client, cmd.Namespace, cmd.ServiceAccount)
 	if err != nil {
 		return err
 	}
 
 	// print kube config
-	return kubeconfig.PrintTokenKubeConfig(c, string(token))
+	return kubeconfig.PrintTokenKubeConfig(c, token)
 }
@@ -0,0 +1,115 @@
+package get
+
+import (
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/kubeconfig"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// ClusterContextCmd holds the flags
+type ClusterContextCmd struct {
+	*flags.GlobalFlags
+
+	Space  string
+	Switch bool
+
+	log log.Logger
+}
+
+// NewClusterContextCmd creates a new command
+func NewClusterContextCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ClusterContextCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+############## loft get cluster-context ###############
+#######################################################
+Returns the name of the kube context for the given loft
+cluster, so it can be used with kubectl --context
+
+Example:
+loft get cluster-context mycluster
+loft get cluster-context mycluster --space myspace
+loft get cluster-context mycluster --switch
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############ devspace get cluster-context #############
+#######################################################
+Returns the name of the kube context for the given loft
+cluster, so it can be used with kubectl --context
+
+Example:
+devspace get cluster-context mycluster
+devspace get cluster-context mycluster --space myspace
+devspace get cluster-context mycluster --switch
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "cluster-context",
+		Short: "Retrieves the kube context name of a loft cluster",
+		Long:  description,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Space, "space", "", "The space to return the kube context for")
+	c.Flags().BoolVar(&cmd.Switch, "switch", false, "If enabled, switches the current kube context to the context of the cluster")
+	return c
+}
+
+// Run executes the functionality
+func (cmd *ClusterContextCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	clusterName := ""
+	if len(args) > 0 {
+		clusterName = args[0]
+	} else {
+		baseClient, err := client.NewClientFromPath(cmd.Config)
+		if err != nil {
+			return err
+		}
+
+		clusterName, err = helper.SelectCluster(baseClient, cmd.log)
+		if err != nil {
+			return err
+		}
+	}
+
+	contextName := kubeconfig.SpaceContextName(clusterName, cmd.Space)
+	if cmd.Switch == false {
+		cmd.log.WriteString(contextName + "\n")
+		return nil
+	}
+
+	exists, err := kubeconfig.ContextExists(contextName)
+	if err != nil {
+		return err
+	} else if exists == false {
+		if cmd.Space != "" {
+			return fmt.Errorf("kube context %s does not exist, please run 'loft use space %s --cluster %s' to create it", contextName, cmd.Space, clusterName)
+		}
+
+		return fmt.Errorf("kube context %s does not exist, please run 'loft use cluster %s' to create it", contextName, clusterName)
+	}
+
+	err = kubeconfig.UseContext(contextName)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Switched kube context to %s", ansi.Color(contextName, "white+b"))
+	return nil
+}
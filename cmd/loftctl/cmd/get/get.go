@@ -29,5 +29,6 @@ func NewGetCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 
 	c.AddCommand(NewUserCmd(globalFlags))
 	c.AddCommand(NewSharedSecretCmd(globalFlags))
+	c.AddCommand(NewClusterContextCmd(globalFlags))
 	return c
 }
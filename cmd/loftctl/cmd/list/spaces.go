@@ -1,20 +1,51 @@
 package list
 
 import (
+	"encoding/json"
+	"fmt"
+
+	clusterv1 "github.com/loft-sh/agentapi/pkg/apis/loft/cluster/v1"
+	managementv1 "github.com/loft-sh/api/pkg/apis/management/v1"
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
 	"github.com/loft-sh/loftctl/pkg/client"
 	"github.com/loft-sh/loftctl/pkg/client/helper"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/duration"
+	"os"
+	"sort"
+	"strings"
 	"time"
 )
 
+// spaceColumns are the columns that can be selected with --columns
+var spaceColumns = []string{"name", "cluster", "sleeping", "status", "age", "namespace", "owner", "labels"}
+
 // SpacesCmd holds the login cmd flags
 type SpacesCmd struct {
 	*flags.GlobalFlags
 
+	NoHeaders  bool
+	Timestamps bool
+	ShowLabels bool
+	Count      bool
+	Output     string
+	Columns    []string
+
+	GroupByCluster bool
+
+	Cluster  string
+	Phases   []string
+	Selector string
+
+	OlderThan time.Duration
+	NewerThan time.Duration
+
+	Stale      bool
+	StaleAfter time.Duration
+
 	log log.Logger
 }
 
@@ -32,6 +63,18 @@ List the loft spaces you have access to
 
 Example:
 loft list spaces
+loft list spaces --no-headers
+loft list spaces -o name
+loft list spaces -o jsonl
+loft list spaces -o wide
+loft list spaces --show-labels
+loft list spaces --group-by-cluster
+loft list spaces --cluster mycluster --phase Failed
+loft list spaces --count --group-by-cluster
+loft list spaces --older-than 720h
+loft list spaces --columns name,owner,age
+loft list spaces --stale --stale-after 336h
+loft list spaces -l team=data,env=prod
 #######################################################
 	`
 	if upgrade.IsPlugin == "true" {
@@ -43,6 +86,18 @@ List the loft spaces you have access to
 
 Example:
 devspace list spaces
+devspace list spaces --no-headers
+devspace list spaces -o name
+devspace list spaces -o jsonl
+devspace list spaces -o wide
+devspace list spaces --show-labels
+devspace list spaces --group-by-cluster
+devspace list spaces --cluster mycluster --phase Failed
+devspace list spaces --count --group-by-cluster
+devspace list spaces --older-than 720h
+devspace list spaces --columns name,owner,age
+devspace list spaces --stale --stale-after 336h
+devspace list spaces -l team=data,env=prod
 #######################################################
 	`
 	}
@@ -56,21 +111,87 @@ devspace list spaces
 		},
 	}
 
+	loginCmd.Flags().BoolVar(&cmd.Timestamps, "timestamps", false, "When enabled, shows RFC3339 timestamps instead of humanized durations")
+	loginCmd.Flags().BoolVar(&cmd.ShowLabels, "show-labels", false, "When enabled, shows the labels of the spaces as last column")
+	loginCmd.Flags().BoolVar(&cmd.GroupByCluster, "group-by-cluster", false, "When enabled, prints a separate table for each cluster")
+	loginCmd.Flags().BoolVar(&cmd.Count, "count", false, "When enabled, only prints the number of spaces (per cluster with --group-by-cluster)")
+	loginCmd.Flags().StringVar(&cmd.Cluster, "cluster", "", "If set, only lists the spaces of this cluster")
+	loginCmd.Flags().DurationVar(&cmd.OlderThan, "older-than", 0, "If set, only lists the spaces that were created longer ago than this duration, e.g. 720h")
+	loginCmd.Flags().DurationVar(&cmd.NewerThan, "newer-than", 0, "If set, only lists the spaces that were created within this duration, e.g. 24h")
+	loginCmd.Flags().BoolVar(&cmd.Stale, "stale", false, "If enabled, only lists the spaces that have been sleeping or inactive for longer than --stale-after")
+	loginCmd.Flags().DurationVar(&cmd.StaleAfter, "stale-after", time.Hour*24*7, "The duration after which a sleeping or inactive space is considered stale")
+	loginCmd.Flags().StringVarP(&cmd.Selector, "selector", "l", "", "If set, only lists the spaces whose labels match this label selector, e.g. team=data,env!=prod")
+	loginCmd.Flags().StringSliceVar(&cmd.Phases, "phase", []string{}, "If set, only lists the spaces in one of these status phases, e.g. Active or Terminating")
+	loginCmd.Flags().StringVarP(&cmd.Output, "output", "o", "", "The output format to use. Valid options are: name, jsonl (one json object per space and line), wide (additionally shows the time since the last activity)")
+	loginCmd.Flags().StringSliceVar(&cmd.Columns, "columns", []string{}, "The columns to show in this order. Valid options are: "+strings.Join(spaceColumns, ", "))
+	loginCmd.Flags().BoolVar(&cmd.NoHeaders, "no-headers", false, "When enabled, the table header row is not printed")
 	return loginCmd
 }
 
 // RunUsers executes the functionality "loft list users"
 func (cmd *SpacesCmd) RunSpaces(cobraCmd *cobra.Command, args []string) error {
+	if cmd.Output != "" && cmd.Output != "name" && cmd.Output != "jsonl" && cmd.Output != "wide" {
+		return fmt.Errorf("unsupported output format %s, valid options are: name, jsonl, wide", cmd.Output)
+	} else if cmd.Count && cmd.Output != "" {
+		return fmt.Errorf("--count cannot be used together with --output")
+	} else if len(cmd.Columns) > 0 && (cmd.Output != "" || cmd.ShowLabels) {
+		return fmt.Errorf("--columns cannot be used together with --output or --show-labels")
+	}
+	for _, column := range cmd.Columns {
+		if isSpaceColumn(column) == false {
+			return fmt.Errorf("unknown column %s, valid options are: %s", column, strings.Join(spaceColumns, ", "))
+		}
+	}
+
+	selector, err := labels.Parse(cmd.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid --selector %s: %v", cmd.Selector, err)
+	}
+
 	baseClient, err := client.NewClientFromPath(cmd.Config)
 	if err != nil {
 		return err
 	}
 
-	spaces, err := helper.GetSpaces(baseClient)
+	// in machine readable output modes, warnings like skipped clusters must not end up in the output
+	warnLog := cmd.log
+	if cmd.Output == "jsonl" || cmd.Output == "name" {
+		warnLog = log.NewLogger(os.Stderr, os.Stderr, cmd.log.GetLevel())
+	}
+
+	if cmd.Output == "jsonl" {
+		// write the spaces of each cluster as soon as they are retrieved, so downstream tools can process them line by line
+		return helper.GetSpacesFunc(baseClient, warnLog, func(clusterSpaces []managementv1.ClusterSpace) error {
+			for _, space := range cmd.filterSpaces(clusterSpaces, selector) {
+				out, err := json.Marshal(space)
+				if err != nil {
+					return err
+				}
+
+				cmd.log.WriteString(string(out) + "\n")
+			}
+
+			return nil
+		})
+	}
+
+	allSpaces, err := helper.GetSpaces(baseClient, warnLog)
 	if err != nil {
 		return err
 	}
 
+	spaces := cmd.filterSpaces(allSpaces, selector)
+	if cmd.Count {
+		cmd.printCount(spaces)
+		return nil
+	} else if cmd.Output == "name" {
+		for _, space := range spaces {
+			cmd.log.WriteString(space.Space.Name + "\n")
+		}
+
+		return nil
+	}
+
 	header := []string{
 		"Name",
 		"Cluster",
@@ -78,23 +199,261 @@ func (cmd *SpacesCmd) RunSpaces(cobraCmd *cobra.Command, args []string) error {
 		"Status",
 		"Age",
 	}
+	if cmd.Timestamps {
+		header[2] = "Sleeping Since"
+		header[4] = "Created"
+	}
+	if cmd.Output == "wide" {
+		header = append(header, "Last Activity")
+	}
+	if cmd.ShowLabels {
+		header = append(header, "Labels")
+	}
+	if len(cmd.Columns) > 0 {
+		titles := map[string]string{
+			"name":      "Name",
+			"cluster":   "Cluster",
+			"sleeping":  header[2],
+			"status":    "Status",
+			"age":       header[4],
+			"namespace": "Namespace",
+			"owner":     "Owner",
+			"labels":    "Labels",
+		}
+
+		header = []string{}
+		for _, column := range cmd.Columns {
+			header = append(header, titles[column])
+		}
+	}
+
 	values := [][]string{}
 	for _, space := range spaces {
 		sleepModeConfig := space.SleepModeConfig
+		if sleepModeConfig == nil {
+			sleepModeConfig = &clusterv1.SleepModeConfig{}
+		}
+
 		sleeping := "false"
 		if sleepModeConfig.Status.SleepingSince != 0 {
-			sleeping = duration.HumanDuration(time.Now().Sub(time.Unix(sleepModeConfig.Status.SleepingSince, 0)))
+			if cmd.Timestamps {
+				sleeping = time.Unix(sleepModeConfig.Status.SleepingSince, 0).UTC().Format(time.RFC3339)
+			} else {
+				sleeping = duration.HumanDuration(time.Now().Sub(time.Unix(sleepModeConfig.Status.SleepingSince, 0)))
+			}
+		}
+
+		age := duration.HumanDuration(time.Now().Sub(space.Space.CreationTimestamp.Time))
+		if cmd.Timestamps {
+			age = space.Space.CreationTimestamp.UTC().Format(time.RFC3339)
 		}
 
-		values = append(values, []string{
+		row := []string{
 			space.Space.Name,
 			space.Cluster,
 			sleeping,
 			string(space.Space.Status.Phase),
-			duration.HumanDuration(time.Now().Sub(space.Space.CreationTimestamp.Time)),
-		})
+			age,
+		}
+		if cmd.Output == "wide" {
+			row = append(row, cmd.formatLastActivity(sleepModeConfig.Status.LastActivity))
+		}
+		if cmd.ShowLabels {
+			row = append(row, formatLabels(space.Space.Labels))
+		}
+		if len(cmd.Columns) > 0 {
+			columnValues := map[string]string{
+				"name":      space.Space.Name,
+				"cluster":   space.Cluster,
+				"sleeping":  sleeping,
+				"status":    string(space.Space.Status.Phase),
+				"age":       age,
+				"namespace": space.Space.Name,
+				"owner":     formatOwner(space.Space.Spec.Account),
+				"labels":    formatLabels(space.Space.Labels),
+			}
+
+			row = []string{}
+			for _, column := range cmd.Columns {
+				row = append(row, columnValues[column])
+			}
+		}
+
+		values = append(values, row)
+	}
+
+	if cmd.GroupByCluster {
+		cmd.printGroupedByCluster(spaces, header, values)
+	} else {
+		cmd.printTable(header, values)
+	}
+	if cmd.NoHeaders == false {
+		cmd.printSummary(spaces)
 	}
 
-	log.PrintTable(cmd.log, header, values)
 	return nil
 }
+
+// printSummary prints the total number of spaces, sleeping spaces and clusters
+func (cmd *SpacesCmd) printSummary(spaces []managementv1.ClusterSpace) {
+	sleeping := 0
+	clusters := map[string]bool{}
+	for _, space := range spaces {
+		if space.SleepModeConfig != nil && space.SleepModeConfig.Status.SleepingSince != 0 {
+			sleeping++
+		}
+
+		clusters[space.Cluster] = true
+	}
+
+	spacesLabel := "spaces"
+	if len(spaces) == 1 {
+		spacesLabel = "space"
+	}
+	clustersLabel := "clusters"
+	if len(clusters) == 1 {
+		clustersLabel = "cluster"
+	}
+
+	cmd.log.WriteString(fmt.Sprintf("\n%d %s (%d sleeping) across %d %s\n", len(spaces), spacesLabel, sleeping, len(clusters), clustersLabel))
+}
+
+func (cmd *SpacesCmd) printTable(header []string, values [][]string) {
+	if cmd.NoHeaders {
+		log.PrintTableWithoutHeader(cmd.log, header, values)
+		return
+	}
+
+	log.PrintTable(cmd.log, header, values)
+}
+
+// printGroupedByCluster prints a separate titled table for each cluster. The values are
+// expected in the same order as the spaces
+func (cmd *SpacesCmd) printGroupedByCluster(spaces []managementv1.ClusterSpace, header []string, values [][]string) {
+	clusters := []string{}
+	valuesByCluster := map[string][][]string{}
+	for i, row := range values {
+		cluster := spaces[i].Cluster
+		if _, ok := valuesByCluster[cluster]; !ok {
+			clusters = append(clusters, cluster)
+		}
+
+		valuesByCluster[cluster] = append(valuesByCluster[cluster], row)
+	}
+	sort.Strings(clusters)
+
+	for _, cluster := range clusters {
+		cmd.log.WriteString("\nCluster: " + cluster + "\n")
+		cmd.printTable(header, valuesByCluster[cluster])
+	}
+}
+
+// printCount prints the number of spaces in total or per cluster
+func (cmd *SpacesCmd) printCount(spaces []managementv1.ClusterSpace) {
+	if cmd.GroupByCluster == false {
+		cmd.log.WriteString(fmt.Sprintf("%d\n", len(spaces)))
+		return
+	}
+
+	clusters := []string{}
+	countByCluster := map[string]int{}
+	for _, space := range spaces {
+		if _, ok := countByCluster[space.Cluster]; !ok {
+			clusters = append(clusters, space.Cluster)
+		}
+
+		countByCluster[space.Cluster]++
+	}
+	sort.Strings(clusters)
+
+	values := [][]string{}
+	for _, cluster := range clusters {
+		values = append(values, []string{cluster, fmt.Sprintf("%d", countByCluster[cluster])})
+	}
+
+	cmd.printTable([]string{"Cluster", "Spaces"}, values)
+}
+
+// formatLastActivity formats the unix timestamp of the last activity of a space
+func (cmd *SpacesCmd) formatLastActivity(lastActivity int64) string {
+	if lastActivity == 0 {
+		return "<unknown>"
+	} else if cmd.Timestamps {
+		return time.Unix(lastActivity, 0).UTC().Format(time.RFC3339)
+	}
+
+	return duration.HumanDuration(time.Now().Sub(time.Unix(lastActivity, 0)))
+}
+
+// formatLabels formats the given labels as sorted k=v pairs like kubectl get --show-labels
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+
+	pairs := []string{}
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// filterSpaces returns the spaces that match the cluster, phase, selector, age and stale filters
+func (cmd *SpacesCmd) filterSpaces(spaces []managementv1.ClusterSpace, selector labels.Selector) []managementv1.ClusterSpace {
+	filtered := []managementv1.ClusterSpace{}
+	for _, space := range spaces {
+		if cmd.Cluster != "" && space.Cluster != cmd.Cluster {
+			continue
+		} else if len(cmd.Phases) > 0 && matchesPhase(string(space.Space.Status.Phase), cmd.Phases) == false {
+			continue
+		} else if selector.Matches(labels.Set(space.Space.Labels)) == false {
+			continue
+		}
+
+		age := time.Now().Sub(space.Space.CreationTimestamp.Time)
+		if cmd.OlderThan > 0 && age <= cmd.OlderThan {
+			continue
+		} else if cmd.NewerThan > 0 && age >= cmd.NewerThan {
+			continue
+		} else if cmd.Stale && helper.SpaceIdleTime(space) <= cmd.StaleAfter {
+			continue
+		}
+
+		filtered = append(filtered, space)
+	}
+
+	return filtered
+}
+
+// formatOwner returns the name of the account that owns the space
+func formatOwner(account string) string {
+	if account == "" {
+		return "<none>"
+	}
+
+	return account
+}
+
+// isSpaceColumn checks if the given column can be selected with --columns
+func isSpaceColumn(column string) bool {
+	for _, c := range spaceColumns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPhase checks if the given phase is one of the phases, ignoring the case
+func matchesPhase(phase string, phases []string) bool {
+	for _, p := range phases {
+		if strings.EqualFold(p, phase) {
+			return true
+		}
+	}
+
+	return false
+}
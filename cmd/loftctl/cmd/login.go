@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	dockerconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
 	managementv1 "github.com/loft-sh/api/pkg/apis/management/v1"
@@ -28,6 +29,7 @@ type LoginCmd struct {
 	Username  string
 	AccessKey string
 	Insecure  bool
+	SSO       bool
 
 	DockerLogin bool
 	Log         log.Logger
@@ -49,6 +51,7 @@ Login into loft
 Example:
 loft login https://my-loft.com
 loft login https://my-loft.com --access-key myaccesskey
+loft login https://my-loft.com --sso
 #######################################################
 	`
 	if upgrade.IsPlugin == "true" {
@@ -61,6 +64,7 @@ Login into loft
 Example:
 devspace login https://my-loft.com
 devspace login https://my-loft.com --access-key myaccesskey
+devspace login https://my-loft.com --sso
 #######################################################
 	`
 	}
@@ -80,6 +84,7 @@ devspace login https://my-loft.com --access-key myaccesskey
 	loginCmd.Flags().StringVar(&cmd.Username, "username", "", "DEPRECATED DO NOT USE ANYMORE")
 	loginCmd.Flags().StringVar(&cmd.AccessKey, "access-key", "", "The access key to use")
 	loginCmd.Flags().BoolVar(&cmd.Insecure, "insecure", false, "Allow login into an insecure loft instance")
+	loginCmd.Flags().BoolVar(&cmd.SSO, "sso", false, "Login via the single sign-on (OIDC) provider configured in loft")
 	loginCmd.Flags().BoolVar(&cmd.DockerLogin, "docker-login", true, "If true, will log into the docker image registries the user has image pull secrets for")
 	return loginCmd
 }
@@ -89,6 +94,9 @@ func (cmd *LoginCmd) RunLogin(cobraCmd *cobra.Command, args []string) error {
 	if cmd.Username != "" {
 		cmd.Log.Warnf("--username is deprecated, please do not use anymore and only use --access-key")
 	}
+	if cmd.SSO && cmd.AccessKey != "" {
+		return fmt.Errorf("--sso cannot be used together with --access-key")
+	}
 
 	loader, err := client.NewClientFromPath(cmd.Config)
 	if err != nil {
@@ -131,6 +139,8 @@ func (cmd *LoginCmd) RunLogin(cobraCmd *cobra.Command, args []string) error {
 	url = strings.TrimSuffix(url, "/")
 	if cmd.AccessKey != "" {
 		err = loader.LoginWithAccessKey(url, cmd.AccessKey, cmd.Insecure)
+	} else if cmd.SSO {
+		err = loader.LoginWithSSO(url, cmd.Insecure, cmd.Log)
 	} else {
 		err = loader.Login(url, cmd.Insecure, cmd.Log)
 	}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sort"
+	"strings"
+)
+
+// loftComponent is a part of a loft installation that runs in its own pods
+type loftComponent struct {
+	LabelSelector string
+	Container     string
+}
+
+// loftComponents are the loft components loft logs can show the logs of
+var loftComponents = map[string]loftComponent{
+	"server": {LabelSelector: "app=loft", Container: "manager"},
+	"agent":  {LabelSelector: "app=loft-agent"},
+}
+
+// LogsCmd holds the cmd flags
+type LogsCmd struct {
+	*flags.GlobalFlags
+
+	Context   string
+	Namespace string
+	Component string
+	Follow    bool
+	Tail      int64
+
+	Log log.Logger
+}
+
+// NewLogsCmd creates a new command
+func NewLogsCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &LogsCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := `
+#######################################################
+###################### loft logs ######################
+#######################################################
+Shows the logs of a loft component
+
+Example:
+loft logs
+loft logs --component agent --follow
+loft logs --context mycontext --namespace loft --tail 100
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+#################### devspace logs ####################
+#######################################################
+Shows the logs of a loft component
+
+Example:
+devspace logs
+devspace logs --component agent --follow
+devspace logs --context mycontext --namespace loft --tail 100
+#######################################################
+	`
+	}
+
+	c := &cobra.Command{
+		Use:   "logs",
+		Short: "Shows the logs of loft",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run()
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Context, "context", "", "The kube context to use")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", "The namespace loft is installed in")
+	c.Flags().StringVar(&cmd.Component, "component", "server", "The loft component to show the logs of. Valid options are: "+strings.Join(loftComponentNames(), ", "))
+	c.Flags().BoolVarP(&cmd.Follow, "follow", "f", false, "If enabled, keeps streaming the logs")
+	c.Flags().Int64Var(&cmd.Tail, "tail", -1, "The number of recent log lines to show, -1 shows all")
+	return c
+}
+
+// Run executes the command logic
+func (cmd *LogsCmd) Run() error {
+	component, ok := loftComponents[cmd.Component]
+	if !ok {
+		return fmt.Errorf("unknown component %s, valid options are: %s", cmd.Component, strings.Join(loftComponentNames(), ", "))
+	}
+
+	overrides := cmd.GlobalFlags.ConfigOverrides()
+	overrides.CurrentContext = cmd.Context
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), overrides)
+	restConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	restConfig.UserAgent = upgrade.UserAgent()
+	kubeClient, err := kubernetes.NewForConfig(kube.WithRequestTracing(restConfig, cmd.Log))
+	if err != nil {
+		return err
+	}
+
+	pods, err := kubeClient.CoreV1().Pods(cmd.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: component.LabelSelector})
+	if err != nil {
+		return errors.Wrap(err, "list pods")
+	} else if len(pods.Items) == 0 {
+		return fmt.Errorf("couldn't find a pod of the loft %s in namespace %s, please make sure you use the correct --context and --namespace", cmd.Component, cmd.Namespace)
+	}
+
+	// show the logs of the newest pod
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.After(pods.Items[j].CreationTimestamp.Time)
+	})
+
+	options := &corev1.PodLogOptions{
+		Container: component.Container,
+		Follow:    cmd.Follow,
+	}
+	if cmd.Tail >= 0 {
+		options.TailLines = &cmd.Tail
+	}
+
+	stream, err := kubeClient.CoreV1().Pods(cmd.Namespace).GetLogs(pods.Items[0].Name, options).Stream(context.TODO())
+	if err != nil {
+		return errors.Wrapf(err, "retrieve logs of pod %s", pods.Items[0].Name)
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(cmd.Log, stream)
+	return err
+}
+
+func loftComponentNames() []string {
+	names := []string{}
+	for name := range loftComponents {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
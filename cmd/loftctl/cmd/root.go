@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/config"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/connect"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/create"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/delete"
@@ -9,10 +11,13 @@ import (
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/list"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/set"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/share"
+	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/spaces"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/use"
 	"github.com/loft-sh/loftctl/cmd/loftctl/cmd/vars"
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
 	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/printhelper"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -25,10 +30,15 @@ func NewRootCmd(log log.Logger) *cobra.Command {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Short:         "Welcome to Loft!",
-		PersistentPreRun: func(cobraCmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cobraCmd *cobra.Command, args []string) error {
 			if globalFlags.Silent {
 				log.SetLevel(logrus.FatalLevel)
 			}
+			log.SetVerbosity(globalFlags.Verbosity)
+			log.SetPlain(globalFlags.Plain)
+			upgrade.SetCacheDir(client.CacheDir(globalFlags.Config))
+			printhelper.NoBanner = globalFlags.NoBanner
+			return resolveClusterAlias(cobraCmd)
 		},
 		Long: `Loft CLI - www.loft.sh`,
 	}
@@ -36,6 +46,45 @@ func NewRootCmd(log log.Logger) *cobra.Command {
 
 var globalFlags *flags.GlobalFlags
 
+// clusterFlags are the flags whose value is a cluster name and can be a cluster alias
+var clusterFlags = []string{"cluster", "target-cluster"}
+
+// resolveClusterAlias replaces the value of the cluster flags of the command with the
+// cluster name if the value is an alias from the loft config
+func resolveClusterAlias(cobraCmd *cobra.Command) error {
+	var clusterAliases map[string]string
+	for _, name := range clusterFlags {
+		clusterFlag := cobraCmd.Flags().Lookup(name)
+		if clusterFlag == nil || clusterFlag.Value.String() == "" {
+			continue
+		}
+
+		if clusterAliases == nil {
+			baseClient, err := client.NewClientFromPath(globalFlags.Config)
+			if err != nil {
+				return err
+			}
+
+			clusterAliases = baseClient.Config().ClusterAliases
+			if clusterAliases == nil {
+				return nil
+			}
+		}
+
+		cluster, ok := clusterAliases[clusterFlag.Value.String()]
+		if !ok {
+			continue
+		}
+
+		err := clusterFlag.Value.Set(cluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -48,17 +97,26 @@ func Execute() {
 	// Execute command
 	err := rootCmd.Execute()
 	if err != nil {
+		message := err.Error()
 		if globalFlags.Debug {
-			log.Fatalf("%+v", err)
-		} else {
-			log.Fatal(err)
+			message = fmt.Sprintf("%+v", err)
+		}
+
+		hint := client.ErrorHint(client.ClassifyError(err))
+		if hint != "" {
+			message += "\n" + hint
 		}
+
+		log.Fatal(message)
 	}
 }
 
-// BuildRoot creates a new root command from the
-func BuildRoot(log log.Logger) *cobra.Command {
-	rootCmd := NewRootCmd(log)
+// BuildRoot creates a new root command from the given logger. The subcommands use the logger as well,
+// so output can be captured by passing a logger created with log.NewLogger
+func BuildRoot(logger log.Logger) *cobra.Command {
+	log.SetInstance(logger)
+	rootCmd := NewRootCmd(logger)
+	rootCmd.SetOut(logger)
 	persistentFlags := rootCmd.PersistentFlags()
 	globalFlags = flags.SetGlobalFlags(persistentFlags)
 
@@ -68,13 +126,18 @@ func BuildRoot(log log.Logger) *cobra.Command {
 	rootCmd.AddCommand(NewTokenCmd(globalFlags))
 	rootCmd.AddCommand(NewSleepCmd(globalFlags))
 	rootCmd.AddCommand(NewWakeUpCmd(globalFlags))
+	rootCmd.AddCommand(NewEventsCmd(globalFlags))
 	rootCmd.AddCommand(NewBackupCmd(globalFlags))
+	rootCmd.AddCommand(NewDoctorCmd(globalFlags))
+	rootCmd.AddCommand(NewLogsCmd(globalFlags))
 	rootCmd.AddCommand(NewCompletionCmd(rootCmd, globalFlags))
 	rootCmd.AddCommand(NewUpgradeCmd())
+	rootCmd.AddCommand(NewVersionCmd())
 
 	// add subcommands
 	rootCmd.AddCommand(connect.NewConnectCmd(globalFlags))
 	rootCmd.AddCommand(list.NewListCmd(globalFlags))
+	rootCmd.AddCommand(spaces.NewSpacesCmd(globalFlags))
 	rootCmd.AddCommand(use.NewUseCmd(globalFlags))
 	rootCmd.AddCommand(create.NewCreateCmd(globalFlags))
 	rootCmd.AddCommand(delete.NewDeleteCmd(globalFlags))
@@ -83,6 +146,7 @@ func BuildRoot(log log.Logger) *cobra.Command {
 	rootCmd.AddCommand(vars.NewVarsCmd(globalFlags))
 	rootCmd.AddCommand(share.NewShareCmd(globalFlags))
 	rootCmd.AddCommand(set.NewSetCmd(globalFlags))
+	rootCmd.AddCommand(config.NewConfigCmd(globalFlags))
 
 	return rootCmd
 }
@@ -2,15 +2,14 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
 	"github.com/loft-sh/loftctl/pkg/client"
 	"github.com/loft-sh/loftctl/pkg/client/helper"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"time"
 )
 
@@ -19,6 +18,7 @@ type SleepCmd struct {
 	*flags.GlobalFlags
 
 	Cluster       string
+	Namespace     string
 	ForceDuration int64
 
 	Log log.Logger
@@ -40,6 +40,7 @@ Sleep puts a space to sleep
 Example:
 loft sleep myspace
 loft sleep myspace --cluster mycluster
+loft sleep myspace --namespace myspace-ns
 #######################################################
 	`
 	if upgrade.IsPlugin == "true" {
@@ -52,6 +53,7 @@ Sleep puts a space to sleep
 Example:
 devspace sleep myspace
 devspace sleep myspace --cluster mycluster
+devspace sleep myspace --namespace myspace-ns
 #######################################################
 	`
 	}
@@ -68,6 +70,7 @@ devspace sleep myspace --cluster mycluster
 
 	c.Flags().Int64Var(&cmd.ForceDuration, "prevent-wakeup", -1, "The amount of seconds this space should sleep until it can be woken up again (use 0 for infinite sleeping). During this time the space can only be woken up by `loft wakeup`, manually deleting the annotation on the namespace or through the loft UI")
 	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster to use")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "", "The namespace of the space sleep mode config, if it differs from the space name")
 	return c
 }
 
@@ -83,7 +86,7 @@ func (cmd *SleepCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		spaceName = args[0]
 	}
 
-	spaceName, clusterName, err := helper.SelectSpaceAndClusterName(baseClient, spaceName, cmd.Cluster, cmd.Log)
+	spaceName, clusterName, err := helper.SelectSpaceAndClusterNameFuzzy(baseClient, spaceName, cmd.Cluster, cmd.Log)
 	if err != nil {
 		return err
 	}
@@ -93,35 +96,37 @@ func (cmd *SleepCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(spaceName).List(context.TODO(), metav1.ListOptions{})
+	namespace := spaceName
+	if cmd.Namespace != "" {
+		namespace = cmd.Namespace
+	}
+
+	sleepModeConfig, err := getSleepModeConfig(clusterClient, namespace)
 	if err != nil {
 		return err
 	}
 
-	sleepModeConfig := &configs.Items[0]
 	sleepModeConfig.Spec.ForceSleep = true
 	if cmd.ForceDuration >= 0 {
 		sleepModeConfig.Spec.ForceSleepDuration = &cmd.ForceDuration
 	}
 
-	sleepModeConfig, err = clusterClient.Agent().ClusterV1().SleepModeConfigs(spaceName).Create(context.TODO(), sleepModeConfig, metav1.CreateOptions{})
+	sleepModeConfig, err = clusterClient.Agent().ClusterV1().SleepModeConfigs(namespace).Create(context.TODO(), sleepModeConfig, metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
 
 	// wait for sleeping
-	cmd.Log.StartWait("Wait until space is sleeping")
-	defer cmd.Log.StopWait()
-	err = wait.Poll(time.Second, time.Minute, func() (bool, error) {
-		configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(spaceName).List(context.TODO(), metav1.ListOptions{})
+	err = util.WaitForCondition(context.TODO(), time.Second, time.Minute, "space "+spaceName+" to start sleeping", cmd.Log, func() (bool, error) {
+		sleepModeConfig, err := getSleepModeConfig(clusterClient, namespace)
 		if err != nil {
 			return false, err
 		}
 
-		return configs.Items[0].Status.SleepingSince != 0, nil
+		return sleepModeConfig.Status.SleepingSince != 0, nil
 	})
 	if err != nil {
-		return fmt.Errorf("error waiting for space to start sleeping: %v", err)
+		return err
 	}
 
 	cmd.Log.Donef("Successfully put space %s to sleep", spaceName)
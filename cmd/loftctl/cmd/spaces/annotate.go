@@ -0,0 +1,72 @@
+package spaces
+
+import (
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+// AnnotateCmd holds the cmd flags
+type AnnotateCmd struct {
+	*flags.GlobalFlags
+
+	Cluster   string
+	Overwrite bool
+
+	log log.Logger
+}
+
+// NewAnnotateCmd creates a new command
+func NewAnnotateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &AnnotateCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################ loft spaces annotate #################
+#######################################################
+Adds, updates or removes annotations of a space. An
+annotation is removed by appending a dash to its key.
+
+Example:
+loft spaces annotate myspace cost-center=1234
+loft spaces annotate myspace cost-center=5678 --overwrite
+loft spaces annotate myspace cost-center- --cluster mycluster
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############## devspace spaces annotate ###############
+#######################################################
+Adds, updates or removes annotations of a space. An
+annotation is removed by appending a dash to its key.
+
+Example:
+devspace spaces annotate myspace cost-center=1234
+devspace spaces annotate myspace cost-center=5678 --overwrite
+devspace spaces annotate myspace cost-center- --cluster mycluster
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "annotate",
+		Short: "Updates the annotations of a space",
+		Long:  description,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster of the space")
+	c.Flags().BoolVar(&cmd.Overwrite, "overwrite", false, "If enabled, existing annotations are overwritten, otherwise changing an existing annotation fails")
+	return c
+}
+
+// Run executes the command
+func (cmd *AnnotateCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	return updateSpaceMetadata(cmd.Config, cmd.Cluster, args[0], "annotations", args[1:], cmd.Overwrite, cmd.log)
+}
@@ -0,0 +1,259 @@
+package spaces
+
+import (
+	"context"
+	"fmt"
+	"github.com/ghodss/yaml"
+	tenancyv1alpha1 "github.com/loft-sh/agentapi/pkg/apis/kiosk/tenancy/v1alpha1"
+	clusterv1 "github.com/loft-sh/agentapi/pkg/apis/loft/cluster/v1"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportCmd holds the cmd flags
+type ImportCmd struct {
+	*flags.GlobalFlags
+
+	Cluster   string
+	DryRun    bool
+	Overwrite bool
+
+	log log.Logger
+}
+
+// NewImportCmd creates a new command
+func NewImportCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ImportCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################# loft spaces import ##################
+#######################################################
+Imports spaces and their sleep mode configs from a yaml
+file or a directory of yaml files into a cluster
+
+Example:
+loft spaces import spaces.yaml --cluster mycluster
+loft spaces import ./exported-spaces --cluster mycluster --dry-run
+loft spaces import spaces.yaml --cluster mycluster --overwrite
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############### devspace spaces import ################
+#######################################################
+Imports spaces and their sleep mode configs from a yaml
+file or a directory of yaml files into a cluster
+
+Example:
+devspace spaces import spaces.yaml --cluster mycluster
+devspace spaces import ./exported-spaces --cluster mycluster --dry-run
+devspace spaces import spaces.yaml --cluster mycluster --overwrite
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "import",
+		Short: "Imports spaces from yaml into a cluster",
+		Long:  description,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster to import the spaces into")
+	c.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If enabled, only prints what would be imported")
+	c.Flags().BoolVar(&cmd.Overwrite, "overwrite", false, "If enabled, existing spaces will be updated instead of skipped")
+	return c
+}
+
+// Run executes the command
+func (cmd *ImportCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	spaces, sleepModeConfigs, err := readSpaceObjects(args[0])
+	if err != nil {
+		return err
+	} else if len(spaces) == 0 && len(sleepModeConfigs) == 0 {
+		return fmt.Errorf("couldn't find any spaces or sleep mode configs in %s", args[0])
+	}
+
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Cluster == "" {
+		cmd.Cluster, err = helper.SelectCluster(baseClient, cmd.log)
+		if err != nil {
+			return err
+		}
+	}
+
+	clusterClient, err := baseClient.Cluster(cmd.Cluster)
+	if err != nil {
+		return err
+	}
+
+	skipped := map[string]bool{}
+	for _, space := range spaces {
+		imported, err := cmd.importSpace(clusterClient, space)
+		if err != nil {
+			return errors.Wrapf(err, "import space %s", space.Name)
+		} else if imported == false {
+			skipped[space.Name] = true
+		}
+	}
+
+	for _, sleepModeConfig := range sleepModeConfigs {
+		if skipped[sleepModeConfig.Namespace] {
+			continue
+		} else if cmd.DryRun {
+			cmd.log.Infof("Would import sleep mode config of space %s", ansi.Color(sleepModeConfig.Namespace, "white+b"))
+			continue
+		}
+
+		_, err = clusterClient.Agent().ClusterV1().SleepModeConfigs(sleepModeConfig.Namespace).Create(context.TODO(), sleepModeConfig, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "import sleep mode config of space %s", sleepModeConfig.Namespace)
+		}
+
+		cmd.log.Donef("Imported sleep mode config of space %s", ansi.Color(sleepModeConfig.Namespace, "white+b"))
+	}
+
+	return nil
+}
+
+func (cmd *ImportCmd) importSpace(clusterClient kube.Interface, space *tenancyv1alpha1.Space) (bool, error) {
+	existing, err := clusterClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), space.Name, metav1.GetOptions{})
+	if err != nil && kerrors.IsNotFound(err) == false {
+		return false, err
+	}
+
+	exists := err == nil
+	if exists && cmd.Overwrite == false {
+		cmd.log.Infof("Skip space %s, because it already exists in cluster %s", ansi.Color(space.Name, "white+b"), cmd.Cluster)
+		return false, nil
+	} else if cmd.DryRun {
+		if exists {
+			cmd.log.Infof("Would update space %s in cluster %s", ansi.Color(space.Name, "white+b"), cmd.Cluster)
+		} else {
+			cmd.log.Infof("Would create space %s in cluster %s", ansi.Color(space.Name, "white+b"), cmd.Cluster)
+		}
+
+		return true, nil
+	}
+
+	if exists {
+		space.ResourceVersion = existing.ResourceVersion
+		_, err = clusterClient.Kiosk().TenancyV1alpha1().Spaces().Update(context.TODO(), space, metav1.UpdateOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		cmd.log.Donef("Updated space %s in cluster %s", ansi.Color(space.Name, "white+b"), cmd.Cluster)
+		return true, nil
+	}
+
+	_, err = clusterClient.Kiosk().TenancyV1alpha1().Spaces().Create(context.TODO(), space, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	cmd.log.Donef("Created space %s in cluster %s", ansi.Color(space.Name, "white+b"), cmd.Cluster)
+	return true, nil
+}
+
+// readSpaceObjects reads all spaces and sleep mode configs from the given file or directory
+func readSpaceObjects(path string) ([]*tenancyv1alpha1.Space, []*clusterv1.SleepModeConfig, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []string{path}
+	if stat.IsDir() {
+		files = []string{}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() == false && (strings.HasSuffix(entry.Name(), ".yaml") || strings.HasSuffix(entry.Name(), ".yml")) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	spaces := []*tenancyv1alpha1.Space{}
+	sleepModeConfigs := []*clusterv1.SleepModeConfig{}
+	for _, file := range files {
+		out, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, document := range strings.Split(string(out), "\n---") {
+			if strings.TrimSpace(document) == "" {
+				continue
+			}
+
+			typeMeta := &metav1.TypeMeta{}
+			err = yaml.Unmarshal([]byte(document), typeMeta)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "parse %s", file)
+			}
+
+			switch typeMeta.Kind {
+			case "Space":
+				space := &tenancyv1alpha1.Space{}
+				err = yaml.Unmarshal([]byte(document), space)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "parse space in %s", file)
+				}
+
+				space.ObjectMeta = cleanObjectMeta(space.ObjectMeta)
+				spaces = append(spaces, space)
+			case "SleepModeConfig":
+				sleepModeConfig := &clusterv1.SleepModeConfig{}
+				err = yaml.Unmarshal([]byte(document), sleepModeConfig)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "parse sleep mode config in %s", file)
+				} else if sleepModeConfig.Namespace == "" {
+					return nil, nil, fmt.Errorf("sleep mode config %s in %s has no namespace", sleepModeConfig.Name, file)
+				}
+
+				sleepModeConfig.ObjectMeta = cleanObjectMeta(sleepModeConfig.ObjectMeta)
+				sleepModeConfigs = append(sleepModeConfigs, sleepModeConfig)
+			}
+		}
+	}
+
+	return spaces, sleepModeConfigs, nil
+}
+
+// cleanObjectMeta removes the cluster specific fields of an exported object
+func cleanObjectMeta(objectMeta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        objectMeta.Name,
+		Namespace:   objectMeta.Namespace,
+		Labels:      objectMeta.Labels,
+		Annotations: objectMeta.Annotations,
+	}
+}
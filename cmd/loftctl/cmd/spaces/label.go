@@ -0,0 +1,162 @@
+package spaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+)
+
+// LabelCmd holds the cmd flags
+type LabelCmd struct {
+	*flags.GlobalFlags
+
+	Cluster   string
+	Overwrite bool
+
+	log log.Logger
+}
+
+// NewLabelCmd creates a new command
+func NewLabelCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &LabelCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################## loft spaces label ##################
+#######################################################
+Adds, updates or removes labels of a space. A label is
+removed by appending a dash to its key.
+
+Example:
+loft spaces label myspace team=backend
+loft spaces label myspace team=frontend --overwrite
+loft spaces label myspace team- --cluster mycluster
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################ devspace spaces label ################
+#######################################################
+Adds, updates or removes labels of a space. A label is
+removed by appending a dash to its key.
+
+Example:
+devspace spaces label myspace team=backend
+devspace spaces label myspace team=frontend --overwrite
+devspace spaces label myspace team- --cluster mycluster
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "label",
+		Short: "Updates the labels of a space",
+		Long:  description,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster of the space")
+	c.Flags().BoolVar(&cmd.Overwrite, "overwrite", false, "If enabled, existing labels are overwritten, otherwise changing an existing label fails")
+	return c
+}
+
+// Run executes the command
+func (cmd *LabelCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	return updateSpaceMetadata(cmd.Config, cmd.Cluster, args[0], "labels", args[1:], cmd.Overwrite, cmd.log)
+}
+
+// updateSpaceMetadata sets or removes the given key=value or key- changes in the labels or annotations of a space via a merge patch
+func updateSpaceMetadata(configPath, clusterName, spaceName, field string, changes []string, overwrite bool, log log.Logger) error {
+	values, err := parseMetadataChanges(changes)
+	if err != nil {
+		return err
+	}
+
+	baseClient, err := client.NewClientFromPath(configPath)
+	if err != nil {
+		return err
+	}
+
+	spaceName, clusterName, err = helper.SelectSpaceAndClusterName(baseClient, spaceName, clusterName, log)
+	if err != nil {
+		return err
+	}
+
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	space, err := clusterClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), spaceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "get space %s", spaceName)
+	}
+
+	existing := space.Labels
+	if field == "annotations" {
+		existing = space.Annotations
+	}
+	if overwrite == false {
+		for key, value := range values {
+			oldValue, ok := existing[key]
+			if ok && value != nil && oldValue != *value {
+				return fmt.Errorf("%s %s of space %s already has a value (%s), use --overwrite to change it", strings.TrimSuffix(field, "s"), key, spaceName, oldValue)
+			}
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			field: values,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = clusterClient.Kiosk().TenancyV1alpha1().Spaces().Patch(context.TODO(), spaceName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "patch space %s", spaceName)
+	}
+
+	log.Donef("Successfully updated the %s of space %s", field, ansi.Color(spaceName, "white+b"))
+	return nil
+}
+
+// parseMetadataChanges parses key=value and key- arguments, removed keys map to nil
+func parseMetadataChanges(changes []string) (map[string]*string, error) {
+	values := map[string]*string{}
+	for _, change := range changes {
+		if strings.Contains(change, "=") {
+			splitted := strings.SplitN(change, "=", 2)
+			if splitted[0] == "" {
+				return nil, fmt.Errorf("invalid argument %s, expected key=value or key-", change)
+			}
+
+			value := splitted[1]
+			values[splitted[0]] = &value
+		} else if strings.HasSuffix(change, "-") && len(change) > 1 {
+			values[strings.TrimSuffix(change, "-")] = nil
+		} else {
+			return nil, fmt.Errorf("invalid argument %s, expected key=value or key-", change)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,173 @@
+package spaces
+
+import (
+	"context"
+	"fmt"
+	tenancyv1alpha1 "github.com/loft-sh/agentapi/pkg/apis/kiosk/tenancy/v1alpha1"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
+	"github.com/mgutz/ansi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MoveCmd holds the cmd flags
+type MoveCmd struct {
+	*flags.GlobalFlags
+
+	Cluster       string
+	TargetCluster string
+	DeleteSource  bool
+
+	log log.Logger
+}
+
+// NewMoveCmd creates a new command
+func NewMoveCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &MoveCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################## loft spaces move ###################
+#######################################################
+Recreates a space with its labels, annotations and
+sleep mode settings in another cluster and asks to
+delete the space in the source cluster afterwards.
+Only the space definition is moved, workloads and data
+inside the space are NOT migrated.
+
+Example:
+loft spaces move myspace --target-cluster mycluster2
+loft spaces move myspace --cluster mycluster --target-cluster mycluster2 --delete-source
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################ devspace spaces move #################
+#######################################################
+Recreates a space with its labels, annotations and
+sleep mode settings in another cluster and asks to
+delete the space in the source cluster afterwards.
+Only the space definition is moved, workloads and data
+inside the space are NOT migrated.
+
+Example:
+devspace spaces move myspace --target-cluster mycluster2
+devspace spaces move myspace --cluster mycluster --target-cluster mycluster2 --delete-source
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "move",
+		Short: "Moves a space definition to another cluster",
+		Long:  description,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster the space is currently in")
+	c.Flags().StringVar(&cmd.TargetCluster, "target-cluster", "", "The cluster to move the space to")
+	c.Flags().BoolVar(&cmd.DeleteSource, "delete-source", false, "If enabled, deletes the space in the source cluster without asking for confirmation")
+	return c
+}
+
+// Run executes the command
+func (cmd *MoveCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	spaceName := ""
+	if len(args) > 0 {
+		spaceName = args[0]
+	}
+
+	spaceName, sourceCluster, err := helper.SelectSpaceAndClusterName(baseClient, spaceName, cmd.Cluster, cmd.log)
+	if err != nil {
+		return err
+	}
+
+	targetCluster := cmd.TargetCluster
+	if targetCluster == "" {
+		targetCluster, err = helper.SelectCluster(baseClient, cmd.log)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = helper.VerifyClusterName(baseClient, targetCluster)
+		if err != nil {
+			return err
+		}
+	}
+	if targetCluster == sourceCluster {
+		return fmt.Errorf("space %s is already in cluster %s", spaceName, targetCluster)
+	}
+
+	sourceClient, err := baseClient.Cluster(sourceCluster)
+	if err != nil {
+		return err
+	}
+	targetClient, err := baseClient.Cluster(targetCluster)
+	if err != nil {
+		return err
+	}
+
+	// export the space from the source cluster
+	space, err := sourceClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), spaceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "get space %s in cluster %s", spaceName, sourceCluster)
+	}
+
+	_, err = targetClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), spaceName, metav1.GetOptions{})
+	if err == nil {
+		return fmt.Errorf("space %s already exists in cluster %s", spaceName, targetCluster)
+	} else if kerrors.IsNotFound(err) == false {
+		return err
+	}
+
+	// owner references point to objects of the source cluster, so they are dropped
+	_, err = targetClient.Kiosk().TenancyV1alpha1().Spaces().Create(context.TODO(), &tenancyv1alpha1.Space{
+		ObjectMeta: cleanObjectMeta(space.ObjectMeta),
+		Spec:       space.Spec,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "create space %s in cluster %s", spaceName, targetCluster)
+	}
+	cmd.log.Donef("Created space %s in cluster %s", ansi.Color(spaceName, "white+b"), ansi.Color(targetCluster, "white+b"))
+
+	err = copySleepModeSettings(sourceClient, spaceName, targetClient, spaceName)
+	if err != nil {
+		cmd.log.Warnf("Couldn't copy the sleep mode settings of space %s: %v", spaceName, err)
+	} else {
+		cmd.log.Donef("Copied the sleep mode settings of space %s", ansi.Color(spaceName, "white+b"))
+	}
+	cmd.log.Warnf("Only the space definition was moved, workloads, volumes and all other resources inside space %s were NOT migrated to cluster %s", spaceName, targetCluster)
+
+	confirmed, err := util.ConfirmDestructive("Do you want to delete space "+spaceName+" in the source cluster "+sourceCluster+" including everything in it?", cmd.DeleteSource || cmd.AssumeYes, cmd.log)
+	if err != nil {
+		return err
+	} else if confirmed == false {
+		cmd.log.Infof("Kept space %s in cluster %s", ansi.Color(spaceName, "white+b"), ansi.Color(sourceCluster, "white+b"))
+		return nil
+	}
+
+	err = deleteSpace(baseClient, sourceCluster, spaceName)
+	if err != nil {
+		return errors.Wrapf(err, "delete space %s in cluster %s", spaceName, sourceCluster)
+	}
+
+	cmd.log.Donef("Deleted space %s in cluster %s", ansi.Color(spaceName, "white+b"), ansi.Color(sourceCluster, "white+b"))
+	return nil
+}
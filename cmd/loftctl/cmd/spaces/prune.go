@@ -0,0 +1,168 @@
+package spaces
+
+import (
+	"context"
+	"fmt"
+	managementv1 "github.com/loft-sh/api/pkg/apis/management/v1"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"time"
+)
+
+// PruneCmd holds the cmd flags
+type PruneCmd struct {
+	*flags.GlobalFlags
+
+	IdleFor time.Duration
+	Cluster string
+	Confirm bool
+
+	log log.Logger
+}
+
+// NewPruneCmd creates a new command
+func NewPruneCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PruneCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################## loft spaces prune ##################
+#######################################################
+Deletes all spaces that have been sleeping or inactive
+for longer than the given duration. By default only
+prints the spaces that would be deleted, use --confirm
+to delete them after a confirmation question.
+
+Example:
+loft spaces prune --idle-for 720h
+loft spaces prune --idle-for 720h --cluster mycluster --confirm
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################ devspace spaces prune ################
+#######################################################
+Deletes all spaces that have been sleeping or inactive
+for longer than the given duration. By default only
+prints the spaces that would be deleted, use --confirm
+to delete them after a confirmation question.
+
+Example:
+devspace spaces prune --idle-for 720h
+devspace spaces prune --idle-for 720h --cluster mycluster --confirm
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "prune",
+		Short: "Deletes spaces that are idle for too long",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().DurationVar(&cmd.IdleFor, "idle-for", 0, "Spaces that have been sleeping or inactive for longer than this duration are pruned, e.g. 720h")
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "If set, only prunes spaces in this cluster")
+	c.Flags().BoolVar(&cmd.Confirm, "confirm", false, "If enabled, actually deletes the spaces instead of only printing them")
+	return c
+}
+
+// Run executes the command
+func (cmd *PruneCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	if cmd.IdleFor <= 0 {
+		return fmt.Errorf("please specify a positive duration via --idle-for, e.g. --idle-for 720h")
+	}
+
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Cluster != "" {
+		err = helper.VerifyClusterName(baseClient, cmd.Cluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	spaces, err := helper.GetSpaces(baseClient, cmd.log)
+	if err != nil {
+		return err
+	}
+
+	idleSpaces := []managementv1.ClusterSpace{}
+	values := [][]string{}
+	for _, space := range spaces {
+		if cmd.Cluster != "" && space.Cluster != cmd.Cluster {
+			continue
+		}
+
+		idleTime := helper.SpaceIdleTime(space)
+		if idleTime < cmd.IdleFor {
+			continue
+		}
+
+		idleSpaces = append(idleSpaces, space)
+		values = append(values, []string{
+			space.Space.Name,
+			space.Cluster,
+			duration.HumanDuration(idleTime),
+		})
+	}
+
+	if len(values) == 0 {
+		cmd.log.Infof("No spaces are idle for longer than %s", cmd.IdleFor.String())
+		return nil
+	}
+
+	log.PrintTable(cmd.log, []string{"Space", "Cluster", "Idle"}, values)
+	if cmd.Confirm == false {
+		cmd.log.Infof("Run with --confirm to delete these spaces")
+		return nil
+	}
+
+	confirmed, err := util.ConfirmDestructive(fmt.Sprintf("Do you want to delete these %d spaces including everything in them?", len(idleSpaces)), cmd.AssumeYes, cmd.log)
+	if err != nil {
+		return err
+	} else if confirmed == false {
+		cmd.log.Info("No spaces were deleted")
+		return nil
+	}
+
+	errs := []error{}
+	for _, space := range idleSpaces {
+		err = deleteSpace(baseClient, space.Cluster, space.Space.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("space %s in cluster %s: %v", space.Space.Name, space.Cluster, err))
+			continue
+		}
+
+		cmd.log.Donef("Deleted space %s in cluster %s", ansi.Color(space.Space.Name, "white+b"), ansi.Color(space.Cluster, "white+b"))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func deleteSpace(baseClient client.Client, clusterName, spaceName string) error {
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	gracePeriod := int64(0)
+	return clusterClient.Kiosk().TenancyV1alpha1().Spaces().Delete(context.TODO(), spaceName, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+}
@@ -0,0 +1,179 @@
+package spaces
+
+import (
+	"context"
+	"fmt"
+	tenancyv1alpha1 "github.com/loft-sh/agentapi/pkg/apis/kiosk/tenancy/v1alpha1"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
+	"github.com/mgutz/ansi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RenameCmd holds the cmd flags
+type RenameCmd struct {
+	*flags.GlobalFlags
+
+	Cluster   string
+	DeleteOld bool
+
+	log log.Logger
+}
+
+// NewRenameCmd creates a new command
+func NewRenameCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &RenameCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################# loft spaces rename ##################
+#######################################################
+Creates a new space with the labels, annotations, owner
+and sleep mode settings of an existing space and asks
+to delete the old space afterwards. Kubernetes cannot
+rename namespaces, so workloads and other resources
+inside the old space are NOT migrated.
+
+Example:
+loft spaces rename myspace mynewspace
+loft spaces rename myspace mynewspace --cluster mycluster --delete-old
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+############### devspace spaces rename ################
+#######################################################
+Creates a new space with the labels, annotations, owner
+and sleep mode settings of an existing space and asks
+to delete the old space afterwards. Kubernetes cannot
+rename namespaces, so workloads and other resources
+inside the old space are NOT migrated.
+
+Example:
+devspace spaces rename myspace mynewspace
+devspace spaces rename myspace mynewspace --cluster mycluster --delete-old
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "rename",
+		Short: "Moves the settings of a space to a new space",
+		Long:  description,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster of the space")
+	c.Flags().BoolVar(&cmd.DeleteOld, "delete-old", false, "If enabled, deletes the old space without asking for confirmation")
+	return c
+}
+
+// Run executes the command
+func (cmd *RenameCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	newName := args[1]
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	oldName, clusterName, err := helper.SelectSpaceAndClusterName(baseClient, args[0], cmd.Cluster, cmd.log)
+	if err != nil {
+		return err
+	} else if oldName == newName {
+		return fmt.Errorf("the new name of space %s is the same as the old one", oldName)
+	}
+
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	oldSpace, err := clusterClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), oldName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "get space %s", oldName)
+	}
+
+	_, err = clusterClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), newName, metav1.GetOptions{})
+	if err == nil {
+		return fmt.Errorf("space %s already exists in cluster %s", newName, clusterName)
+	} else if kerrors.IsNotFound(err) == false {
+		return err
+	}
+
+	// create the new space with the metadata of the old one
+	newSpace := &tenancyv1alpha1.Space{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            newName,
+			Labels:          oldSpace.Labels,
+			Annotations:     oldSpace.Annotations,
+			OwnerReferences: oldSpace.OwnerReferences,
+		},
+		Spec: oldSpace.Spec,
+	}
+	_, err = clusterClient.Kiosk().TenancyV1alpha1().Spaces().Create(context.TODO(), newSpace, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "create space %s", newName)
+	}
+	cmd.log.Donef("Created space %s with the labels, annotations and owner of space %s", ansi.Color(newName, "white+b"), ansi.Color(oldName, "white+b"))
+
+	err = copySleepModeSettings(clusterClient, oldName, clusterClient, newName)
+	if err != nil {
+		cmd.log.Warnf("Couldn't copy the sleep mode settings of space %s: %v", oldName, err)
+	} else {
+		cmd.log.Donef("Copied the sleep mode settings of space %s", ansi.Color(oldName, "white+b"))
+	}
+	cmd.log.Warnf("Workloads, secrets, config maps, volumes and role bindings inside space %s were not migrated", oldName)
+
+	confirmed, err := util.ConfirmDestructive("Do you want to delete the old space "+oldName+" including everything in it?", cmd.DeleteOld || cmd.AssumeYes, cmd.log)
+	if err != nil {
+		return err
+	} else if confirmed == false {
+		cmd.log.Infof("Kept the old space %s", ansi.Color(oldName, "white+b"))
+		return nil
+	}
+
+	err = deleteSpace(baseClient, clusterName, oldName)
+	if err != nil {
+		return errors.Wrapf(err, "delete space %s", oldName)
+	}
+
+	cmd.log.Donef("Deleted the old space %s", ansi.Color(oldName, "white+b"))
+	return nil
+}
+
+// copySleepModeSettings copies the sleep mode spec of the old space to the new space, which can be in another cluster
+func copySleepModeSettings(oldClusterClient kube.Interface, oldName string, newClusterClient kube.Interface, newName string) error {
+	oldConfigs, err := oldClusterClient.Agent().ClusterV1().SleepModeConfigs(oldName).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	} else if len(oldConfigs.Items) == 0 {
+		return nil
+	}
+
+	newConfigs, err := newClusterClient.Agent().ClusterV1().SleepModeConfigs(newName).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	} else if len(newConfigs.Items) == 0 {
+		return fmt.Errorf("couldn't find the sleep mode config of space %s", newName)
+	}
+
+	sleepModeConfig := &newConfigs.Items[0]
+	sleepModeConfig.Spec = oldConfigs.Items[0].Spec
+	sleepModeConfig.Spec.ForceSleep = false
+	sleepModeConfig.Spec.ForceSleepDuration = nil
+	_, err = newClusterClient.Agent().ClusterV1().SleepModeConfigs(newName).Create(context.TODO(), sleepModeConfig, metav1.CreateOptions{})
+	return err
+}
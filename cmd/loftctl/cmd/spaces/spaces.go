@@ -0,0 +1,39 @@
+package spaces
+
+import (
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+// NewSpacesCmd creates a new cobra command
+func NewSpacesCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	description := `
+#######################################################
+##################### loft spaces #####################
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################### devspace spaces ###################
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "spaces",
+		Short: "Manage loft spaces",
+		Long:  description,
+		Args:  cobra.NoArgs,
+	}
+
+	c.AddCommand(NewAnnotateCmd(globalFlags))
+	c.AddCommand(NewImportCmd(globalFlags))
+	c.AddCommand(NewLabelCmd(globalFlags))
+	c.AddCommand(NewMoveCmd(globalFlags))
+	c.AddCommand(NewPruneCmd(globalFlags))
+	c.AddCommand(NewRenameCmd(globalFlags))
+	c.AddCommand(NewTopCmd(globalFlags))
+	c.AddCommand(NewWatchActivityCmd(globalFlags))
+	return c
+}
@@ -0,0 +1,247 @@
+package spaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sort"
+	"strings"
+)
+
+// TopCmd holds the cmd flags
+type TopCmd struct {
+	*flags.GlobalFlags
+
+	Cluster string
+	Limit   int
+	SortBy  string
+
+	log log.Logger
+}
+
+// spaceUsage is the summed up resource usage of all pods in a space
+type spaceUsage struct {
+	Space   string
+	Cluster string
+	CPU     resource.Quantity
+	Memory  resource.Quantity
+
+	// NoMetrics is true if the cluster of the space has no metrics api
+	NoMetrics bool
+}
+
+// podMetricsList is the subset of the metrics.k8s.io PodMetricsList we need
+type podMetricsList struct {
+	Items []struct {
+		Containers []struct {
+			Usage map[string]resource.Quantity `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// NewTopCmd creates a new command
+func NewTopCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &TopCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+################### loft spaces top ###################
+#######################################################
+Shows the cpu and memory usage of the spaces you have
+access to, sorted descending. Requires the metrics
+server to be installed in the clusters.
+
+Example:
+loft spaces top
+loft spaces top --cluster mycluster --limit 10
+loft spaces top --sort-by memory
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+################# devspace spaces top #################
+#######################################################
+Shows the cpu and memory usage of the spaces you have
+access to, sorted descending. Requires the metrics
+server to be installed in the clusters.
+
+Example:
+devspace spaces top
+devspace spaces top --cluster mycluster --limit 10
+devspace spaces top --sort-by memory
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "top",
+		Short: "Shows the resource usage of spaces",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "If set, only shows the spaces in this cluster")
+	c.Flags().IntVar(&cmd.Limit, "limit", 0, "If set, only shows the top N spaces")
+	c.Flags().StringVar(&cmd.SortBy, "sort-by", "cpu", "The resource to sort by. Valid options are: cpu, memory")
+	return c
+}
+
+// Run executes the command
+func (cmd *TopCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	if cmd.SortBy != "cpu" && cmd.SortBy != "memory" {
+		return fmt.Errorf("unsupported --sort-by %s, valid options are: cpu, memory", cmd.SortBy)
+	}
+
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Cluster != "" {
+		err = helper.VerifyClusterName(baseClient, cmd.Cluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	spaces, err := helper.GetSpaces(baseClient, cmd.log)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.StartWait("Retrieving space metrics")
+	usages := []spaceUsage{}
+	metricsAvailable := map[string]bool{}
+	clustersWithoutMetrics := []string{}
+	for _, space := range spaces {
+		if cmd.Cluster != "" && space.Cluster != cmd.Cluster {
+			continue
+		}
+
+		clusterClient, err := baseClient.Cluster(space.Cluster)
+		if err != nil {
+			cmd.log.StopWait()
+			return err
+		}
+
+		// clusters without metrics server are shown as N/A instead of failing
+		available, ok := metricsAvailable[space.Cluster]
+		if !ok {
+			available, err = hasMetricsAPI(clusterClient)
+			if err != nil {
+				cmd.log.StopWait()
+				return errors.Wrapf(err, "discover metrics api of cluster %s", space.Cluster)
+			} else if available == false {
+				clustersWithoutMetrics = append(clustersWithoutMetrics, space.Cluster)
+			}
+
+			metricsAvailable[space.Cluster] = available
+		}
+		if available == false {
+			usages = append(usages, spaceUsage{Space: space.Space.Name, Cluster: space.Cluster, NoMetrics: true})
+			continue
+		}
+
+		usage, err := getSpaceUsage(clusterClient, space.Space.Name)
+		if err != nil {
+			cmd.log.StopWait()
+			return errors.Wrapf(err, "retrieve metrics of space %s in cluster %s, please make sure the metrics server is installed", space.Space.Name, space.Cluster)
+		}
+
+		usage.Cluster = space.Cluster
+		usages = append(usages, usage)
+	}
+	cmd.log.StopWait()
+	if len(clustersWithoutMetrics) > 0 {
+		cmd.log.Warnf("The metrics api is not available in cluster %s, please install the metrics server to see the usage of its spaces", strings.Join(clustersWithoutMetrics, ", "))
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].NoMetrics != usages[j].NoMetrics {
+			return usages[j].NoMetrics
+		} else if cmd.SortBy == "memory" {
+			return usages[i].Memory.Cmp(usages[j].Memory) > 0
+		}
+
+		return usages[i].CPU.Cmp(usages[j].CPU) > 0
+	})
+	if cmd.Limit > 0 && len(usages) > cmd.Limit {
+		usages = usages[:cmd.Limit]
+	}
+
+	values := [][]string{}
+	for _, usage := range usages {
+		if usage.NoMetrics {
+			values = append(values, []string{usage.Space, usage.Cluster, "N/A", "N/A"})
+			continue
+		}
+
+		values = append(values, []string{
+			usage.Space,
+			usage.Cluster,
+			fmt.Sprintf("%dm", usage.CPU.MilliValue()),
+			fmt.Sprintf("%dMi", usage.Memory.Value()/(1024*1024)),
+		})
+	}
+
+	log.PrintTable(cmd.log, []string{"Space", "Cluster", "CPU", "Memory"}, values)
+	return nil
+}
+
+// hasMetricsAPI checks via discovery if the metrics api is served in the cluster
+func hasMetricsAPI(clusterClient kube.Interface) (bool, error) {
+	_, err := clusterClient.Discovery().ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1")
+	if err != nil {
+		// the api service might be registered without a running metrics server
+		if kerrors.IsNotFound(err) || kerrors.IsServiceUnavailable(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getSpaceUsage sums up the current usage of all pods in the space namespace from the metrics api
+func getSpaceUsage(clusterClient kube.Interface, spaceName string) (spaceUsage, error) {
+	usage := spaceUsage{Space: spaceName}
+	out, err := clusterClient.CoreV1().RESTClient().Get().AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces/" + spaceName + "/pods").DoRaw(context.TODO())
+	if err != nil {
+		return usage, err
+	}
+
+	metrics := &podMetricsList{}
+	err = json.Unmarshal(out, metrics)
+	if err != nil {
+		return usage, err
+	}
+
+	for _, pod := range metrics.Items {
+		for _, container := range pod.Containers {
+			if cpu, ok := container.Usage["cpu"]; ok {
+				usage.CPU.Add(cpu)
+			}
+			if memory, ok := container.Usage["memory"]; ok {
+				usage.Memory.Add(memory)
+			}
+		}
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,196 @@
+package spaces
+
+import (
+	"context"
+	"fmt"
+	clusterv1 "github.com/loft-sh/agentapi/pkg/apis/loft/cluster/v1"
+	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
+	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"time"
+)
+
+// WatchActivityCmd holds the cmd flags
+type WatchActivityCmd struct {
+	*flags.GlobalFlags
+
+	Cluster string
+
+	// sleeping holds the last known sleep state per space
+	sleeping map[string]bool
+
+	log log.Logger
+}
+
+// NewWatchActivityCmd creates a new command
+func NewWatchActivityCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &WatchActivityCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+	description := `
+#######################################################
+############# loft spaces watch-activity ##############
+#######################################################
+Watches the spaces of a cluster and prints every time
+a space goes to sleep or wakes up.
+
+Example:
+loft spaces watch-activity
+loft spaces watch-activity --cluster mycluster
+#######################################################
+	`
+	if upgrade.IsPlugin == "true" {
+		description = `
+#######################################################
+########### devspace spaces watch-activity ############
+#######################################################
+Watches the spaces of a cluster and prints every time
+a space goes to sleep or wakes up.
+
+Example:
+devspace spaces watch-activity
+devspace spaces watch-activity --cluster mycluster
+#######################################################
+	`
+	}
+	c := &cobra.Command{
+		Use:   "watch-activity",
+		Short: "Prints when spaces go to sleep or wake up",
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd, args)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster to watch")
+	return c
+}
+
+// Run executes the command
+func (cmd *WatchActivityCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	baseClient, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+
+	clusterName := cmd.Cluster
+	if clusterName == "" {
+		clusterName, err = helper.SelectCluster(baseClient, cmd.log)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = helper.VerifyClusterName(baseClient, clusterName)
+		if err != nil {
+			return err
+		}
+	}
+
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	cmd.sleeping = map[string]bool{}
+	cmd.log.Infof("Watching the activity of the spaces in cluster %s", ansi.Color(clusterName, "white+b"))
+	backoff := newRelistBackoff()
+	for {
+		started := time.Now()
+		err = cmd.watch(clusterClient)
+		if err != nil {
+			return err
+		}
+
+		// a watch that ran for a while was closed regularly, so we don't need to slow down
+		if time.Since(started) > relistBackoffCap {
+			backoff = newRelistBackoff()
+		}
+
+		time.Sleep(backoff.Step())
+	}
+}
+
+// relistBackoffCap is the maximum delay before the sleep mode configs are listed again
+const relistBackoffCap = time.Second * 30
+
+// newRelistBackoff returns the backoff between relists of the sleep mode configs, so a watch
+// that keeps failing doesn't hammer the api server
+func newRelistBackoff() *wait.Backoff {
+	return &wait.Backoff{
+		Duration: time.Second,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    10,
+		Cap:      relistBackoffCap,
+	}
+}
+
+// watch lists the sleep mode configs of the cluster and watches them until the watch is closed by the server
+// or fails with an error event, in both cases the caller lists and watches again
+func (cmd *WatchActivityCmd) watch(clusterClient kube.Interface) error {
+	configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range configs.Items {
+		cmd.update(&configs.Items[i])
+	}
+
+	watcher, err := clusterClient.Agent().ClusterV1().SleepModeConfigs("").Watch(context.TODO(), metav1.ListOptions{ResourceVersion: configs.ResourceVersion})
+	if kerrors.IsResourceExpired(err) || kerrors.IsGone(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Error:
+			err = kerrors.FromObject(event.Object)
+			if kerrors.IsResourceExpired(err) == false && kerrors.IsGone(err) == false {
+				cmd.log.Debugf("Restarting the watch of the sleep mode configs: %v", err)
+			}
+
+			return nil
+		case watch.Added, watch.Modified:
+			if sleepModeConfig, ok := event.Object.(*clusterv1.SleepModeConfig); ok {
+				cmd.update(sleepModeConfig)
+			}
+		case watch.Deleted:
+			if sleepModeConfig, ok := event.Object.(*clusterv1.SleepModeConfig); ok {
+				delete(cmd.sleeping, sleepModeConfig.Namespace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// update prints a line if the sleep state of the space has changed since it was last seen
+func (cmd *WatchActivityCmd) update(sleepModeConfig *clusterv1.SleepModeConfig) {
+	space := sleepModeConfig.Namespace
+	sleeping := sleepModeConfig.Status.SleepingSince != 0
+	wasSleeping, known := cmd.sleeping[space]
+	cmd.sleeping[space] = sleeping
+	if known == false || wasSleeping == sleeping {
+		return
+	}
+
+	if sleeping {
+		cmd.log.WriteString(fmt.Sprintf("%s space %s went to sleep\n", time.Unix(sleepModeConfig.Status.SleepingSince, 0).Format(time.RFC3339), ansi.Color(space, "white+b")))
+	} else {
+		cmd.log.WriteString(fmt.Sprintf("%s space %s woke up\n", time.Now().Format(time.RFC3339), ansi.Color(space, "white+b")))
+	}
+}
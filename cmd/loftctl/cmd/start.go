@@ -2,26 +2,36 @@ package cmd
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/loft-sh/loftctl/pkg/clihelper"
 	"github.com/loft-sh/loftctl/pkg/printhelper"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
 	"github.com/pkg/errors"
+	"io/ioutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/rest"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
 	"github.com/loft-sh/loftctl/pkg/client"
+	"github.com/loft-sh/loftctl/pkg/kube"
+	"github.com/loft-sh/loftctl/pkg/kubeconfig"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/survey"
+	"github.com/loft-sh/loftctl/pkg/util"
+	"github.com/mgutz/ansi"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -33,6 +43,8 @@ type StartCmd struct {
 	*flags.GlobalFlags
 
 	LocalPort   string
+	Host        string
+	Email       string
 	Reset       bool
 	Version     string
 	Context     string
@@ -41,6 +53,50 @@ type StartCmd struct {
 	Values      string
 	ReuseValues bool
 	Upgrade     bool
+	ChartName   string
+	ChartRepo   string
+
+	StorageClass string
+	Offline      bool
+	Atomic       bool
+	DNSCheck     bool
+	WaitForLB    bool
+	ValuesDebug  bool
+	PrintCommand bool
+
+	PurgeNamespace   bool
+	Force            bool
+	RemoveFinalizers bool
+	ResetTimeout     time.Duration
+	ConnectTimeout   time.Duration
+
+	SetAdminAccessKey  bool
+	AdminAccessKeyFile string
+
+	AddRepos     []string
+	repoArgs     []string
+	RepoUsername string
+	RepoPassword string
+	PostRenderer string
+
+	Image               string
+	ImageTag            string
+	ImagePullSecret     string
+	ImagePullSecretFile string
+
+	ExtraManifests []string
+
+	IngressAnnotations []string
+
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+
+	impersonatedKubeConfig string
+
+	SkipIngressController bool
+	IngressClass          string
 
 	// Will be filled later
 	KubeClient kubernetes.Interface
@@ -48,6 +104,24 @@ type StartCmd struct {
 	Log        log.Logger
 }
 
+// InstallMode describes how loft is reached after the installation
+type InstallMode string
+
+const (
+	// InstallModeLocal means loft is reached via port-forwarding
+	InstallModeLocal InstallMode = "local"
+	// InstallModeRemote means loft is reached via its ingress host
+	InstallModeRemote InstallMode = "remote"
+)
+
+// InstallResult is the outcome of a loft installation
+type InstallResult struct {
+	Host     string
+	Password string
+	Mode     InstallMode
+	URL      string
+}
+
 // NewStartCmd creates a new command
 func NewStartCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	cmd := &StartCmd{
@@ -72,12 +146,31 @@ before running this command:
 2. Helm v3 must be installed
 3. kubectl must be installed
 
+For air-gapped environments use --offline together with
+--repo (internal chart mirror) or --chart (local chart).
+In offline mode, loft start will not check GitHub for
+a newer loft CLI version and will not install the
+ingress-nginx controller from its public helm repository.
+The only remaining network connections are the ones to
+your Kubernetes cluster and the given chart repository.
+
+All flags can also be set via LOFT_* environment
+variables, e.g. LOFT_NAMESPACE or LOFT_LOCAL_PORT.
+Explicitly set flags take precedence. Destructive flags
+like --reset, --force, --purge-namespace and
+--remove-finalizers can only be set explicitly.
+
 #######################################################
 	`,
 		Args: cobra.NoArgs,
+		PreRunE: func(cobraCmd *cobra.Command, args []string) error {
+			return applyEnvironmentVariables(cobraCmd.LocalFlags())
+		},
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			// Check for newer version
-			upgrade.PrintNewerVersionWarning()
+			if cmd.Offline == false {
+				upgrade.PrintNewerVersionWarning()
+			}
 
 			return cmd.Run(cobraCmd, args)
 		},
@@ -85,19 +178,139 @@ before running this command:
 
 	startCmd.Flags().StringVar(&cmd.Context, "context", "", "The kube context to use for installation")
 	startCmd.Flags().StringVar(&cmd.Namespace, "namespace", "loft", "The namespace to install loft into")
-	startCmd.Flags().StringVar(&cmd.LocalPort, "local-port", "9898", "The local port to bind to if using port-forwarding")
+	startCmd.Flags().StringVar(&cmd.LocalPort, "local-port", "9898", "The local port to bind to if using port-forwarding. Use 0 to pick a free port")
+	startCmd.Flags().StringVar(&cmd.Host, "host", "", "The host loft should be reachable at via ingress. If empty, loft start will ask for it")
+	startCmd.Flags().StringVar(&cmd.Email, "email", "", "The email address of the admin user. If empty, loft start will ask for it")
 	startCmd.Flags().StringVar(&cmd.Password, "password", "", "The password to use for the admin account. (If empty this will be the namespace UID)")
 	startCmd.Flags().StringVar(&cmd.Version, "version", "", "The loft version to install")
 	startCmd.Flags().StringVar(&cmd.Values, "values", "", "Path to a file for extra loft helm chart values")
+	startCmd.Flags().StringVar(&cmd.StorageClass, "storage-class", "", "The storage class to use for the loft persistent volume claim. If empty, the default storage class of the cluster is used")
 	startCmd.Flags().BoolVar(&cmd.ReuseValues, "reuse-values", true, "Reuse previous Loft helm values on upgrade")
 	startCmd.Flags().BoolVar(&cmd.Upgrade, "upgrade", false, "If true, Loft will try to upgrade the release")
 	startCmd.Flags().BoolVar(&cmd.Reset, "reset", false, "If true, an existing loft instance will be deleted before installing loft")
+	startCmd.Flags().BoolVar(&cmd.PurgeNamespace, "purge-namespace", false, "If true, the loft namespace will be deleted after uninstalling loft with --reset")
+	startCmd.Flags().DurationVar(&cmd.ResetTimeout, "reset-timeout", 2*time.Minute, "How long loft start waits with --reset until the loft validating webhook and apiservice are deleted")
+	startCmd.Flags().BoolVar(&cmd.RemoveFinalizers, "remove-finalizers", false, "If true, loft start removes the finalizers of the loft namespace and space namespaces that are stuck terminating during the reset")
+	startCmd.Flags().DurationVar(&cmd.ConnectTimeout, "connect-timeout", 10*time.Second, "How long loft start waits for the kubernetes api server to respond during the initial cluster checks")
+	startCmd.Flags().BoolVar(&cmd.Force, "force", false, "If true, loft start will not ask for confirmation before resetting loft or deleting the loft namespace and installs loft into namespaces that are already used by other workloads")
+	startCmd.Flags().BoolVar(&cmd.PrintCommand, "print-command", false, "If true, loft start will print the kubectl port-forward command that can be used to reach loft manually")
+	startCmd.Flags().BoolVar(&cmd.DNSCheck, "dns-check", true, "If true, loft start will report if the loft host resolves while waiting for DNS to be configured")
+	startCmd.Flags().BoolVar(&cmd.ValuesDebug, "values-debug", false, "If true, loft start only prints the merged helm values of the loft release via a helm dry run and exits without changing anything in the cluster. Uses the local values if --host is not set")
+	startCmd.Flags().BoolVar(&cmd.SetAdminAccessKey, "set-admin-access-key", false, "If true, loft start creates an access key for the admin user after loft is ready and prints it")
+	startCmd.Flags().StringVar(&cmd.AdminAccessKeyFile, "admin-access-key-file", "", "If set, the access key created by --set-admin-access-key is written to this file instead of printed")
+	startCmd.Flags().BoolVar(&cmd.WaitForLB, "wait-for-lb", false, "If true, loft start will wait until the ingress-nginx load balancer has an external address and print it in the DNS instructions")
+	startCmd.Flags().BoolVar(&cmd.Atomic, "atomic", true, "If true, helm will roll back a failed loft install or upgrade automatically")
+	startCmd.Flags().StringVar(&cmd.ChartName, "chart", clihelper.DefaultChartName, "The loft chart to install. Can also be a path to a local chart")
+	startCmd.Flags().StringVar(&cmd.ChartRepo, "repo", clihelper.DefaultChartRepo, "The helm repository to install the loft chart from")
+	startCmd.Flags().StringVar(&cmd.RepoUsername, "repo-username", "", "The username to authenticate against the helm repository of the loft chart")
+	startCmd.Flags().StringVar(&cmd.RepoPassword, "repo-password", "", "The password to authenticate against the helm repository of the loft chart")
+	startCmd.Flags().StringArrayVar(&cmd.AddRepos, "add-repo", []string{}, "Additional helm repositories in the form name=url that are needed for dependencies of the loft chart. Can be used multiple times")
+	startCmd.Flags().StringVar(&cmd.PostRenderer, "post-renderer", "", "Path to an executable that is passed to helm as --post-renderer to patch the rendered loft manifests")
+	startCmd.Flags().StringVar(&cmd.Image, "image", "", "The loft image to use, e.g. my-registry.com/loftsh/loft")
+	startCmd.Flags().StringVar(&cmd.ImageTag, "image-tag", "", "The tag of the loft image to use")
+	startCmd.Flags().StringVar(&cmd.ImagePullSecret, "image-pull-secret", "", "The name of the image pull secret in the loft namespace to pull the loft image with")
+	startCmd.Flags().StringVar(&cmd.ImagePullSecretFile, "image-pull-secret-file", "", "Path to a docker config.json to create or update the image pull secret of --image-pull-secret from")
+	startCmd.Flags().StringArrayVar(&cmd.ExtraManifests, "extra-manifest", []string{}, "Path to a yaml file with additional manifests that are applied to the cluster after loft is ready. Can be used multiple times")
+	startCmd.Flags().StringArrayVar(&cmd.IngressAnnotations, "ingress-annotation", []string{}, "Extra annotations in the form key=value for the loft ingress. Can be used multiple times")
+	startCmd.Flags().StringVar(&cmd.CPURequest, "cpu-request", "", "The cpu request of the loft container, e.g. 200m")
+	startCmd.Flags().StringVar(&cmd.CPULimit, "cpu-limit", "", "The cpu limit of the loft container, e.g. 2")
+	startCmd.Flags().StringVar(&cmd.MemoryRequest, "memory-request", "", "The memory request of the loft container, e.g. 256Mi")
+	startCmd.Flags().StringVar(&cmd.MemoryLimit, "memory-limit", "", "The memory limit of the loft container, e.g. 2Gi")
+	startCmd.Flags().BoolVar(&cmd.SkipIngressController, "skip-ingress-controller", false, "If true, loft start will not ask to install the nginx ingress controller and assumes an ingress controller already exists in the cluster")
+	startCmd.Flags().StringVar(&cmd.IngressClass, "ingress-class", "", "The ingress class of an existing ingress controller the loft ingress should use. If set, loft start will not ask to install the nginx ingress controller")
+	startCmd.Flags().BoolVar(&cmd.Offline, "offline", false, "If true, loft start will not check for a newer CLI version and will not install an ingress controller from a public repository. Requires --repo to point to an internal mirror or --chart to be a local chart")
 	return startCmd
 }
 
 // Run executes the functionality "loft start"
 func (cmd *StartCmd) Run(cobraCmd *cobra.Command, args []string) error {
-	err := cmd.prepare()
+	var err error
+	if cmd.PurgeNamespace && cmd.Reset == false {
+		return fmt.Errorf("--purge-namespace can only be used together with --reset")
+	}
+	if cmd.RemoveFinalizers && cmd.Reset == false {
+		return fmt.Errorf("--remove-finalizers can only be used together with --reset")
+	}
+	if cmd.ValuesDebug && (cmd.Reset || cmd.Upgrade) {
+		return fmt.Errorf("--values-debug cannot be used together with --reset or --upgrade")
+	}
+	if (cmd.RepoUsername != "" || cmd.RepoPassword != "") && cmd.ChartRepo == "" {
+		return fmt.Errorf("--repo-username and --repo-password can only be used together with --repo")
+	}
+	if cmd.ImagePullSecretFile != "" && cmd.ImagePullSecret == "" {
+		return fmt.Errorf("--image-pull-secret-file can only be used together with --image-pull-secret")
+	}
+	if cmd.AdminAccessKeyFile != "" && cmd.SetAdminAccessKey == false {
+		return fmt.Errorf("--admin-access-key-file can only be used together with --set-admin-access-key")
+	}
+	for _, annotation := range cmd.IngressAnnotations {
+		if splitted := strings.SplitN(annotation, "=", 2); len(splitted) != 2 || splitted[0] == "" {
+			return fmt.Errorf("invalid ingress annotation %s, expected the form key=value", annotation)
+		}
+	}
+	if cmd.PostRenderer != "" {
+		_, err = exec.LookPath(cmd.PostRenderer)
+		if err != nil {
+			return fmt.Errorf("post renderer %s is not an executable: %v", cmd.PostRenderer, err)
+		}
+	}
+	for _, manifest := range cmd.ExtraManifests {
+		_, err = os.Stat(manifest)
+		if err != nil {
+			return fmt.Errorf("extra manifest %s cannot be read: %v", manifest, err)
+		}
+	}
+	for _, resourceFlag := range cmd.resourceFlags() {
+		if resourceFlag.Quantity == "" {
+			continue
+		}
+
+		_, err = resource.ParseQuantity(resourceFlag.Quantity)
+		if err != nil {
+			return fmt.Errorf("invalid quantity %s for --%s: %v", resourceFlag.Quantity, resourceFlag.Flag, err)
+		}
+	}
+
+	err = cmd.prepare()
+	if cmd.impersonatedKubeConfig != "" {
+		defer os.Remove(cmd.impersonatedKubeConfig)
+	}
+	if err != nil {
+		return err
+	}
+
+	// validate the values file before running helm
+	if cmd.Values != "" {
+		err = clihelper.ValidateHelmValuesFile(cmd.Values, cmd.Log)
+		if err != nil {
+			return err
+		}
+	}
+
+	// add the helm repositories for chart dependencies
+	if len(cmd.AddRepos) > 0 {
+		repoArgs, cleanup, err := clihelper.AddHelmRepos(cmd.AddRepos, cmd.Log)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		cmd.repoArgs = repoArgs
+	}
+
+	// only print the values before anything in the cluster is changed
+	if cmd.ValuesDebug {
+		return cmd.printValuesDebug()
+	}
+
+	// check if loft is installed in another namespace
+	err = cmd.checkOtherNamespaces()
+	if err != nil {
+		return err
+	}
+
+	// wait for an interrupted installation that is still in progress
+	err = cmd.waitForPendingHelmRelease()
 	if err != nil {
 		return err
 	}
@@ -108,22 +321,477 @@ func (cmd *StartCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		return err
 	} else if isInstalled {
 		if cmd.Reset == false {
-			return cmd.handleAlreadyExistingInstallation()
+			result, err := cmd.handleAlreadyExistingInstallation()
+			if err != nil {
+				return err
+			}
+
+			return cmd.success(result)
+		}
+
+		cmd.Log.Info("Found an existing loft installation")
+		err = cmd.confirmReset()
+		if err != nil {
+			return err
+		}
+
+		err = cmd.handleStuckNamespaces()
+		if err != nil {
+			return err
+		}
+
+		err = clihelper.UninstallLoft(cmd.KubeClient, cmd.RestConfig, cmd.Context, cmd.Namespace, cmd.ResetTimeout, cmd.Log)
+		if err != nil {
+			return err
+		}
+
+		if cmd.PurgeNamespace {
+			err = cmd.purgeNamespace()
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		err = cmd.handleLeftoverHelmRelease()
+		if err != nil {
+			return err
+		}
+
+		err = cmd.checkNamespaceUsage()
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.ImagePullSecretFile != "" {
+		err = clihelper.EnsureImagePullSecret(cmd.KubeClient, cmd.Namespace, cmd.ImagePullSecret, cmd.ImagePullSecretFile)
+		if err != nil {
+			return errors.Wrap(err, "create image pull secret")
+		}
+
+		cmd.Log.Donef("Saved image pull secret %s in namespace %s", cmd.ImagePullSecret, cmd.Namespace)
+	}
+
+	if cmd.NoBanner == false {
+		cmd.Log.WriteString("\n")
+		cmd.Log.Info("Welcome to the loft installation.")
+		cmd.Log.Info("This installer will guide you through the installation.")
+		cmd.Log.Info("If you prefer installing loft via helm yourself, visit https://loft.sh/docs/getting-started/setup")
+		cmd.Log.Info("Thanks for trying out loft!")
+	}
+
+	installLocally := false
+	remoteHost := strings.TrimSuffix(strings.TrimPrefix(cmd.Host, "https://"), "/")
+	if remoteHost == "" {
+		installLocally, remoteHost, err = cmd.askForHost()
+		if err != nil {
+			return err
+		}
+	}
+
+	userEmail := cmd.Email
+	if userEmail == "" {
+		userEmail, err = cmd.Log.Question(&survey.QuestionOptions{
+			Question: "Enter an email address for your admin user",
+			ValidationFunc: func(emailVal string) error {
+				if !emailRegex.MatchString(emailVal) {
+					return fmt.Errorf("%s is not a valid email address", emailVal)
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+	} else if emailRegex.MatchString(userEmail) == false {
+		return fmt.Errorf("%s is not a valid email address", userEmail)
+	}
+
+	var result *InstallResult
+	if installLocally || remoteHost == "" {
+		result, err = cmd.installLocal(userEmail)
+	} else {
+		result, err = cmd.installRemote(userEmail, remoteHost)
+	}
+	if err != nil {
+		return err
+	}
+
+	return cmd.success(result)
+}
+
+// confirmReset shows which kube context and namespace are about to be reset and asks the user
+// for confirmation, because the reset also deletes cluster-scoped resources
+func (cmd *StartCmd) confirmReset() error {
+	if cmd.PurgeNamespace && protectedNamespaces[cmd.Namespace] {
+		return fmt.Errorf("refusing to delete namespace %s, please remove the leftover loft resources manually", cmd.Namespace)
+	}
+
+	cmd.Log.WriteString("\n")
+	cmd.Log.Warnf("Resetting loft in kube context %s and namespace %s will delete:", ansi.Color(cmd.Context, "white+b"), ansi.Color(cmd.Namespace, "white+b"))
+	cmd.Log.Warnf("- the loft helm release in namespace %s", cmd.Namespace)
+	cmd.Log.Warn("- the validating webhook configuration loft")
+	cmd.Log.Warnf("- the apiservice %s", clihelper.LoftAPIServiceName)
+	cmd.Log.Warn("- the loft user admin")
+	if cmd.PurgeNamespace {
+		cmd.Log.Warnf("- the namespace %s including all resources in it", cmd.Namespace)
+	}
+	cmd.Log.WriteString("\n")
+
+	confirmed, err := util.ConfirmDestructive(fmt.Sprintf("Do you really want to reset loft in kube context %s and namespace %s?", cmd.Context, cmd.Namespace), cmd.Force || cmd.AssumeYes, cmd.Log)
+	if err != nil {
+		return err
+	} else if confirmed == false {
+		return fmt.Errorf("aborted resetting loft, run with --yes to skip the confirmation")
+	}
+
+	return nil
+}
+
+// protectedNamespaces are namespaces that are never deleted by --purge-namespace
+var protectedNamespaces = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// purgeNamespace deletes the loft namespace including all leftover resources and waits until it is gone
+func (cmd *StartCmd) purgeNamespace() error {
+	if protectedNamespaces[cmd.Namespace] {
+		return fmt.Errorf("refusing to delete namespace %s, please remove the leftover loft resources manually", cmd.Namespace)
+	}
+
+	err := cmd.KubeClient.CoreV1().Namespaces().Delete(context.TODO(), cmd.Namespace, metav1.DeleteOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrap(err, "delete namespace")
+	}
+
+	// if the namespace is still there after the grace period, it is most likely stuck on finalizers
+	err = cmd.waitForNamespaceDeletion(clihelper.TerminatingGracePeriod + time.Minute)
+	if err != nil {
+		err = cmd.handleStuckNamespaces()
+		if err != nil {
+			return err
+		}
+
+		err = cmd.waitForNamespaceDeletion(time.Minute * 10)
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd.Log.Donef("Successfully deleted namespace %s", cmd.Namespace)
+	return nil
+}
+
+func (cmd *StartCmd) waitForNamespaceDeletion(timeout time.Duration) error {
+	return util.WaitForCondition(context.TODO(), time.Second, timeout, "namespace "+cmd.Namespace+" to be deleted", cmd.Log, func() (bool, error) {
+		_, err := cmd.KubeClient.CoreV1().Namespaces().Get(context.TODO(), cmd.Namespace, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return true, nil
+			}
+
+			return false, err
+		}
+
+		return false, nil
+	})
+}
+
+// handleStuckNamespaces reports the loft and space namespaces that are stuck terminating together with what
+// blocks them. With --remove-finalizers their finalizers are removed, otherwise a stuck loft namespace fails the reset.
+func (cmd *StartCmd) handleStuckNamespaces() error {
+	stuckNamespaces, err := clihelper.FindStuckNamespaces(cmd.KubeClient, cmd.Namespace)
+	if err != nil {
+		return err
+	} else if len(stuckNamespaces) == 0 {
+		return nil
+	}
+
+	loftNamespaceStuck := false
+	for _, namespace := range stuckNamespaces {
+		cmd.Log.Warnf("Namespace %s is stuck terminating, finalizers: %s", ansi.Color(namespace.Name, "white+b"), strings.Join(namespace.Finalizers, ", "))
+		for _, reason := range namespace.Reasons {
+			cmd.Log.Warnf("- %s", reason)
+		}
+
+		if namespace.Name == cmd.Namespace {
+			loftNamespaceStuck = true
+		}
+	}
+
+	if cmd.RemoveFinalizers == false {
+		if loftNamespaceStuck {
+			return fmt.Errorf("namespace %s is stuck terminating, please remove the blocking finalizers or run with --remove-finalizers to remove the namespace finalizers", cmd.Namespace)
+		}
+
+		cmd.Log.Warn("Run with --remove-finalizers to remove the finalizers of these namespaces")
+		return nil
+	}
+
+	for _, namespace := range stuckNamespaces {
+		err = clihelper.RemoveNamespaceFinalizers(cmd.KubeClient, namespace.Name)
+		if err != nil {
+			return errors.Wrapf(err, "remove finalizers of namespace %s", namespace.Name)
+		}
+
+		cmd.Log.Donef("Removed finalizers of namespace %s", namespace.Name)
+	}
+
+	return nil
+}
+
+func (cmd *StartCmd) prepare() error {
+	if cmd.Offline && cmd.ChartRepo == clihelper.DefaultChartRepo {
+		_, err := os.Stat(cmd.ChartName)
+		if err != nil {
+			return fmt.Errorf("--offline requires --repo to point to an internal chart mirror or --chart to be a path to a local loft chart")
+		}
+
+		// a local chart does not need a repository
+		cmd.ChartRepo = ""
+	}
+
+	loader, err := client.NewClientFromPath(cmd.Config)
+	if err != nil {
+		return err
+	}
+	loftConfig := loader.Config()
+
+	// first load the kube config
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+
+	// load the raw config
+	kubeConfig, err := kubeClientConfig.RawConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	// we switch the context to the install config
+	contextToLoad := kubeConfig.CurrentContext
+	if cmd.Context != "" {
+		contextToLoad = cmd.Context
+	} else if contextToLoad == "" {
+		contextNames := []string{}
+		for name := range kubeConfig.Contexts {
+			contextNames = append(contextNames, name)
 		}
+		if len(contextNames) == 0 {
+			return fmt.Errorf("there is no current context set in your kube config and no contexts are available, please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working")
+		}
+		sort.Strings(contextNames)
+
+		defaultValue := contextNames[0]
+		if kubeConfig.Contexts[loftConfig.LastInstallContext] != nil {
+			defaultValue = loftConfig.LastInstallContext
+		}
+
+		contextToLoad, err = cmd.Log.Question(&survey.QuestionOptions{
+			Question:     "Seems like there is no current context set in your kube config. Please choose which kubernetes context you want to use",
+			DefaultValue: defaultValue,
+			Options:      contextNames,
+		})
+		if err != nil {
+			return fmt.Errorf("there is no current context set in your kube config, please specify one of the following contexts via --context: %s", strings.Join(contextNames, ", "))
+		}
+	} else if loftConfig.LastInstallContext != "" && loftConfig.LastInstallContext != contextToLoad {
+		contextToLoad, err = cmd.Log.Question(&survey.QuestionOptions{
+			Question:     "Seems like you try to use 'loft start' with a different kubernetes context than before. Please choose which kubernetes context you want to use",
+			DefaultValue: contextToLoad,
+			Options:      []string{contextToLoad, loftConfig.LastInstallContext},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cmd.Context = contextToLoad
+
+	loftConfig.LastInstallContext = contextToLoad
+	_ = loader.Save()
+
+	// kube client config
+	kubeClientConfig = clientcmd.NewNonInteractiveClientConfig(kubeConfig, contextToLoad, cmd.GlobalFlags.ConfigOverrides(), clientcmd.NewDefaultClientConfigLoadingRules())
+
+	// let helm and kubectl run as the impersonated identity as well
+	if cmd.As != "" || len(cmd.AsGroups) > 0 {
+		cmd.impersonatedKubeConfig, err = kubeconfig.WriteImpersonatedKubeConfig(kubeConfig, contextToLoad, cmd.As, cmd.AsGroups)
+		if err != nil {
+			return errors.Wrap(err, "write impersonated kube config")
+		}
+
+		err = os.Setenv("KUBECONFIG", cmd.impersonatedKubeConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	// test for helm and kubectl
+	_, err = exec.LookPath("helm")
+	if err != nil {
+		return fmt.Errorf("seems like helm is not installed. Helm is required for the installation of loft. Please visit https://helm.sh/docs/intro/install/ for install instructions")
+	}
+
+	output, err := exec.Command("helm", "version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("seems like there are issues with your helm client: \n\n%s", output)
+	} else if cmd.Log.GetVerbosity() >= log.VerbosityDetailed {
+		cmd.Log.Debugf("Executed command: helm version\n%s", output)
+	}
+
+	_, err = exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("seems like kubectl is not installed. Kubectl is required for the installation of loft. Please visit https://kubernetes.io/docs/tasks/tools/install-kubectl/ for install instructions")
+	}
+
+	output, err = exec.Command("kubectl", "version", "--context", contextToLoad, "--request-timeout", cmd.ConnectTimeout.String()).CombinedOutput()
+	if err != nil {
+		if isAuthenticationFailure(string(output)) {
+			return expiredCredentialsError(contextToLoad, strings.TrimSpace(string(output)))
+		} else if isConnectTimeout(string(output)) {
+			return unreachableClusterError(contextToLoad, cmd.ConnectTimeout, strings.TrimSpace(string(output)))
+		}
+
+		return fmt.Errorf("Seems like kubectl cannot connect to your Kubernetes cluster: \n\n%s", output)
+	} else if cmd.Log.GetVerbosity() >= log.VerbosityDetailed {
+		cmd.Log.Debugf("Executed command: kubectl version --context %s --request-timeout %s\n%s", contextToLoad, cmd.ConnectTimeout.String(), output)
+	}
+
+	cmd.RestConfig, err = kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+	cmd.RestConfig.UserAgent = upgrade.UserAgent()
+	cmd.RestConfig = kube.WithRequestTracing(cmd.RestConfig, cmd.Log)
+	cmd.KubeClient, err = kubernetes.NewForConfig(cmd.RestConfig)
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	// Check if cluster has RBAC correctly configured, with a short timeout so an unreachable cluster fails fast
+	checkConfig := rest.CopyConfig(cmd.RestConfig)
+	checkConfig.Timeout = cmd.ConnectTimeout
+	checkClient, err := kubernetes.NewForConfig(checkConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = checkClient.RbacV1().ClusterRoles().Get(context.Background(), "cluster-admin", metav1.GetOptions{})
+	if kerrors.IsUnauthorized(err) {
+		return expiredCredentialsError(contextToLoad, err.Error())
+	} else if err != nil && isConnectTimeout(err.Error()) {
+		return unreachableClusterError(contextToLoad, cmd.ConnectTimeout, err.Error())
+	} else if err != nil {
+		return fmt.Errorf("error retrieving cluster role 'cluster-admin': %v. Please make sure RBAC is correctly configured in your cluster", err)
+	}
+
+	// without a default storage class the loft pvc would stay pending
+	if cmd.StorageClass == "" {
+		hasDefault, err := clihelper.HasDefaultStorageClass(cmd.KubeClient)
+		if err != nil {
+			cmd.Log.Debugf("Error checking for a default storage class: %v", err)
+		} else if hasDefault == false {
+			cmd.Log.Warnf("Seems like there is no default storage class in your cluster, which means the loft persistent volume claim might stay pending. Please specify a storage class via --storage-class")
+		}
+	}
+
+	return nil
+}
+
+// environmentIgnoredFlags are destructive flags that are never set from a LOFT_* environment variable,
+// so a leftover variable in a CI environment cannot wipe an installation
+var environmentIgnoredFlags = map[string]bool{
+	"reset":             true,
+	"force":             true,
+	"purge-namespace":   true,
+	"remove-finalizers": true,
+}
+
+// applyEnvironmentVariables sets all flags that were not set explicitly from the corresponding
+// LOFT_* environment variable, e.g. --local-port from LOFT_LOCAL_PORT
+func applyEnvironmentVariables(flagSet *pflag.FlagSet) error {
+	var err error
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || environmentIgnoredFlags[flag.Name] {
+			return
+		}
+
+		envName := "LOFT_" + strings.ToUpper(strings.ReplaceAll(flag.Name, "-", "_"))
+		value, ok := os.LookupEnv(envName)
+		if !ok {
+			return
+		}
+
+		setErr := flagSet.Set(flag.Name, value)
+		if setErr != nil {
+			err = fmt.Errorf("invalid value %s in environment variable %s: %v", value, envName, setErr)
+		}
+	})
+
+	return err
+}
+
+// authenticationFailures are messages of kubectl and client-go auth plugins that indicate invalid or expired credentials
+var authenticationFailures = []string{
+	"unauthorized",
+	"the server has asked for the client to provide credentials",
+	"you must be logged in to the server",
+	"token has expired",
+	"token is expired",
+	"expired token",
+}
+
+// isAuthenticationFailure checks if the given kubectl output is caused by invalid or expired credentials
+func isAuthenticationFailure(output string) bool {
+	output = strings.ToLower(output)
+	for _, failure := range authenticationFailures {
+		if strings.Contains(output, failure) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expiredCredentialsError returns an error that tells the user to renew the credentials of the kube context
+func expiredCredentialsError(kubeContext, details string) error {
+	return fmt.Errorf("the credentials of kube context %s seem to be expired or invalid (%s). Please authenticate again with your cloud provider, e.g. 'gcloud auth login', 'aws sso login' or 'az login', and make sure 'kubectl get namespaces' is working", kubeContext, details)
+}
 
-		cmd.Log.Info("Found an existing loft installation")
-		err = clihelper.UninstallLoft(cmd.KubeClient, cmd.RestConfig, cmd.Context, cmd.Namespace, cmd.Log)
-		if err != nil {
-			return err
+// connectTimeouts are messages of kubectl and client-go that indicate the api server didn't respond in time.
+// "unable to connect to the server" is not one of them, as kubectl prefixes other errors like x509 or dns failures with it
+var connectTimeouts = []string{
+	"i/o timeout",
+	"deadline exceeded",
+	"timeout awaiting",
+	"client.timeout exceeded",
+}
+
+// isConnectTimeout checks if the given kubectl output or error is caused by an unreachable api server
+func isConnectTimeout(output string) bool {
+	output = strings.ToLower(output)
+	for _, timeout := range connectTimeouts {
+		if strings.Contains(output, timeout) {
+			return true
 		}
 	}
 
-	cmd.Log.WriteString("\n")
-	cmd.Log.Info("Welcome to the loft installation.")
-	cmd.Log.Info("This installer will guide you through the installation.")
-	cmd.Log.Info("If you prefer installing loft via helm yourself, visit https://loft.sh/docs/getting-started/setup")
-	cmd.Log.Info("Thanks for trying out loft!")
+	return false
+}
 
+// unreachableClusterError returns an error that tells the user the api server of the kube context couldn't be reached
+func unreachableClusterError(kubeContext string, timeout time.Duration, details string) error {
+	return fmt.Errorf("the kubernetes api server of kube context %s didn't respond within %s (%s). Please make sure the cluster is reachable, e.g. that you are connected to the correct VPN, or increase the timeout via --connect-timeout", kubeContext, timeout.String(), details)
+}
+
+// askForHost asks the user if loft should be installed locally or remotely and returns the host
+// loft should be reachable at
+func (cmd *StartCmd) askForHost() (bool, string, error) {
 	installLocally := clihelper.IsLocalCluster(cmd.RestConfig.Host, cmd.Log)
 	remoteHost := ""
 
@@ -142,13 +810,13 @@ func (cmd *StartCmd) Run(cobraCmd *cobra.Command, args []string) error {
 			},
 		})
 		if err != nil {
-			return err
+			return false, "", err
 		}
 
 		if answer == YesOption {
 			remoteHost, err = clihelper.AskForHost(cmd.Log)
 			if err != nil {
-				return err
+				return false, "", err
 			} else if remoteHost == "" {
 				installLocally = true
 			}
@@ -170,7 +838,7 @@ func (cmd *StartCmd) Run(cobraCmd *cobra.Command, args []string) error {
 			},
 		})
 		if err != nil {
-			return err
+			return false, "", err
 		}
 
 		if answer == NoOption {
@@ -178,111 +846,154 @@ func (cmd *StartCmd) Run(cobraCmd *cobra.Command, args []string) error {
 
 			remoteHost, err = clihelper.AskForHost(cmd.Log)
 			if err != nil {
-				return err
+				return false, "", err
 			} else if remoteHost == "" {
 				installLocally = true
 			}
 		}
 	}
 
-	userEmail, err := cmd.Log.Question(&survey.QuestionOptions{
-		Question: "Enter an email address for your admin user",
-		ValidationFunc: func(emailVal string) error {
-			if !emailRegex.MatchString(emailVal) {
-				return fmt.Errorf("%s is not a valid email address", emailVal)
-			}
-			return nil
-		},
-	})
+	return installLocally, remoteHost, nil
+}
+
+// checkOtherNamespaces checks if loft is already installed in a different namespace than the target
+// namespace and offers to use that namespace instead, because loft uses cluster wide resources like
+// the apiservice and webhooks that would conflict with a second installation
+func (cmd *StartCmd) checkOtherNamespaces() error {
+	namespaces, err := clihelper.FindLoftInstallations(cmd.KubeClient)
 	if err != nil {
 		return err
 	}
 
-	if installLocally || remoteHost == "" {
-		return cmd.installLocal(userEmail)
+	otherNamespaces := []string{}
+	for _, namespace := range namespaces {
+		if namespace == cmd.Namespace {
+			return nil
+		}
+
+		otherNamespaces = append(otherNamespaces, namespace)
+	}
+	if len(otherNamespaces) == 0 {
+		return nil
 	}
 
-	return cmd.installRemote(userEmail, remoteHost)
-}
+	cmd.Log.Warnf("Found an existing loft installation in namespace %s, installing loft a second time into namespace %s will conflict with it", strings.Join(otherNamespaces, ", "), cmd.Namespace)
+	continueOption := "No, install loft into namespace " + cmd.Namespace + " anyway"
+	options := append(otherNamespaces, continueOption)
 
-func (cmd *StartCmd) prepare() error {
-	loader, err := client.NewClientFromPath(cmd.Config)
+	answer, err := cmd.Log.Question(&survey.QuestionOptions{
+		Question:     "Do you want to use the existing loft installation in one of these namespaces instead?",
+		DefaultValue: options[0],
+		Options:      options,
+	})
 	if err != nil {
 		return err
+	} else if answer != continueOption {
+		cmd.Log.Infof("Using namespace %s", answer)
+		cmd.Namespace = answer
 	}
-	loftConfig := loader.Config()
 
-	// first load the kube config
-	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	return nil
+}
 
-	// load the raw config
-	kubeConfig, err := kubeClientConfig.RawConfig()
+// checkNamespaceUsage makes sure loft is not installed by accident into a namespace that is already used by other workloads
+func (cmd *StartCmd) checkNamespaceUsage() error {
+	foreignResources, err := clihelper.FindForeignResources(cmd.KubeClient, cmd.Namespace)
 	if err != nil {
-		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+		return err
+	} else if len(foreignResources) == 0 {
+		return nil
 	}
 
-	// we switch the context to the install config
-	contextToLoad := kubeConfig.CurrentContext
-	if cmd.Context != "" {
-		contextToLoad = cmd.Context
-	} else if loftConfig.LastInstallContext != "" && loftConfig.LastInstallContext != contextToLoad {
-		contextToLoad, err = cmd.Log.Question(&survey.QuestionOptions{
-			Question:     "Seems like you try to use 'loft start' with a different kubernetes context than before. Please choose which kubernetes context you want to use",
-			DefaultValue: contextToLoad,
-			Options:      []string{contextToLoad, loftConfig.LastInstallContext},
-		})
-		if err != nil {
-			return err
-		}
+	cmd.Log.Warnf("Namespace %s already contains resources that do not belong to loft:", cmd.Namespace)
+	for _, foreignResource := range foreignResources {
+		cmd.Log.Warnf("- %s", foreignResource)
+	}
+	if cmd.Force == false {
+		return fmt.Errorf("refusing to install loft into namespace %s, please choose another namespace with --namespace or run with --force to install loft there anyway", cmd.Namespace)
 	}
-	cmd.Context = contextToLoad
-
-	loftConfig.LastInstallContext = contextToLoad
-	_ = loader.Save()
 
-	// kube client config
-	kubeClientConfig = clientcmd.NewNonInteractiveClientConfig(kubeConfig, contextToLoad, &clientcmd.ConfigOverrides{}, clientcmd.NewDefaultClientConfigLoadingRules())
+	return nil
+}
 
-	// test for helm and kubectl
-	_, err = exec.LookPath("helm")
+// waitForPendingHelmRelease waits until a loft helm release that is still installing or upgrading,
+// e.g. because a previous loft start was interrupted, has settled
+func (cmd *StartCmd) waitForPendingHelmRelease() error {
+	status, err := clihelper.GetHelmReleaseStatus(cmd.Context, cmd.Namespace, "loft")
 	if err != nil {
-		return fmt.Errorf("seems like helm is not installed. Helm is required for the installation of loft. Please visit https://helm.sh/docs/intro/install/ for install instructions")
+		return err
+	} else if strings.HasPrefix(status, "pending") == false {
+		return nil
 	}
 
-	output, err := exec.Command("helm", "version").CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("seems like there are issues with your helm client: \n\n%s", output)
-	}
+	cmd.Log.Infof("Found a loft helm release with status '%s', seems like a previous installation is still in progress", status)
+	err = util.WaitForCondition(context.TODO(), time.Second*5, time.Minute*10, "the loft helm release to settle", cmd.Log, func() (bool, error) {
+		status, err = clihelper.GetHelmReleaseStatus(cmd.Context, cmd.Namespace, "loft")
+		if err != nil {
+			return false, err
+		}
 
-	_, err = exec.LookPath("kubectl")
+		return strings.HasPrefix(status, "pending") == false, nil
+	})
 	if err != nil {
-		return fmt.Errorf("seems like kubectl is not installed. Kubectl is required for the installation of loft. Please visit https://kubernetes.io/docs/tasks/tools/install-kubectl/ for install instructions")
+		return err
 	}
 
-	output, err = exec.Command("kubectl", "version", "--context", contextToLoad).CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("Seems like kubectl cannot connect to your Kubernetes cluster: \n\n%s", output)
+	if status == "" {
+		cmd.Log.Info("The loft helm release has been removed")
+	} else {
+		cmd.Log.Infof("The loft helm release has settled with status '%s'", status)
 	}
 
-	cmd.RestConfig, err = kubeClientConfig.ClientConfig()
-	if err != nil {
-		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
-	}
-	cmd.KubeClient, err = kubernetes.NewForConfig(cmd.RestConfig)
+	return nil
+}
+
+// handleLeftoverHelmRelease checks if there is a loft helm release without a loft deployment, which
+// happens if a previous installation was interrupted, and removes it if it would block the installation
+func (cmd *StartCmd) handleLeftoverHelmRelease() error {
+	status, err := clihelper.GetHelmReleaseStatus(cmd.Context, cmd.Namespace, "loft")
 	if err != nil {
-		return fmt.Errorf("there is an error loading your current kube config (%v), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+		return err
+	} else if status == "" {
+		return nil
 	}
 
-	// Check if cluster has RBAC correctly configured
-	_, err = cmd.KubeClient.RbacV1().ClusterRoles().Get(context.Background(), "cluster-admin", metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("error retrieving cluster role 'cluster-admin': %v. Please make sure RBAC is correctly configured in your cluster", err)
+	cmd.Log.Infof("Found an existing loft helm release with status '%s' but no loft deployment, a previous installation was probably interrupted", status)
+
+	// a pending release blocks every helm operation, so we can only reset it
+	pending := strings.HasPrefix(status, "pending")
+	if cmd.Reset == false && pending == false {
+		const (
+			UpgradeOption = "Upgrade the existing helm release"
+			ResetOption   = "Uninstall the existing helm release and install loft again"
+		)
+
+		answer, err := cmd.Log.Question(&survey.QuestionOptions{
+			Question:     "How do you want to continue?",
+			DefaultValue: UpgradeOption,
+			Options: []string{
+				UpgradeOption,
+				ResetOption,
+			},
+		})
+		if err != nil {
+			return err
+		} else if answer == UpgradeOption {
+			return nil
+		}
+	} else if cmd.Reset == false {
+		confirmed, err := util.ConfirmDestructive("The helm release is still pending and has to be uninstalled first. Do you want to uninstall it now?", cmd.Force || cmd.AssumeYes, cmd.Log)
+		if err != nil {
+			return err
+		} else if confirmed == false {
+			return fmt.Errorf("cannot install loft while the helm release is pending, please run 'loft start --reset' to uninstall it")
+		}
 	}
 
-	return nil
+	return clihelper.UninstallHelmRelease(cmd.Context, cmd.Namespace, "loft", cmd.Log)
 }
 
-func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
+func (cmd *StartCmd) handleAlreadyExistingInstallation() (*InstallResult, error) {
 	cmd.Log.Info("Found an existing loft installation, if you want to reinstall loft run 'loft start --reset'")
 	cmd.Log.Info("Found an existing loft installation, if you want to upgrade loft run 'loft start --upgrade'")
 
@@ -291,7 +1002,7 @@ func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
 	if password == "" {
 		defaultPassword, err := clihelper.GetLoftDefaultPassword(cmd.KubeClient, cmd.Namespace)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		password = defaultPassword
@@ -310,10 +1021,11 @@ func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
 		if cmd.Values != "" {
 			extraArgs = append(extraArgs, "--values", cmd.Values)
 		}
+		extraArgs = append(extraArgs, cmd.helmExtraArgs()...)
 
-		err := clihelper.UpgradeLoft(cmd.Context, cmd.Namespace, extraArgs, cmd.Log)
+		err := clihelper.UpgradeLoft(cmd.ChartName, cmd.ChartRepo, cmd.Context, cmd.Namespace, extraArgs, cmd.Log)
 		if err != nil {
-			return errors.Wrap(err, "upgrade loft")
+			return nil, errors.Wrap(err, "upgrade loft")
 		}
 	} else if isLocal {
 		// ask if we should deploy an ingress now
@@ -331,16 +1043,16 @@ func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
 			},
 		})
 		if err != nil {
-			return err
+			return nil, err
 		} else if answer == YesOption {
 			host, err := clihelper.EnterHostNameQuestion(cmd.Log)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			err = cmd.upgradeWithIngress(host)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
@@ -351,17 +1063,17 @@ func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
 	// wait until Loft is ready
 	loftPod, err := cmd.waitForLoft(password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// check if local or remote installation
 	if isLocal {
 		err = cmd.startPortForwarding(loftPod)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		return cmd.successLocal(password)
+		return cmd.localInstallResult(password), nil
 	}
 
 	// get login link
@@ -369,19 +1081,19 @@ func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
 	host, err := clihelper.GetLoftIngressHost(cmd.KubeClient, cmd.Namespace)
 	cmd.Log.StopWait()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// check if loft is reachable
-	reachable, err := clihelper.IsLoftReachable(host)
-	if reachable == false || err != nil {
+	status := clihelper.CheckLoftReachability(host)
+	if status.Reachability != clihelper.LoftReachable {
 		const (
 			YesOption = "Yes"
 			NoOption  = "No, I want to see the DNS message again"
 		)
 
 		answer, err := cmd.Log.Question(&survey.QuestionOptions{
-			Question:     "Loft seems to be not reachable at https://" + host + ". Do you want to use port-forwarding instead?",
+			Question:     "Loft seems to be not reachable at https://" + host + ": " + status.String() + ". Do you want to use port-forwarding instead?",
 			DefaultValue: YesOption,
 			Options: []string{
 				YesOption,
@@ -389,20 +1101,20 @@ func (cmd *StartCmd) handleAlreadyExistingInstallation() error {
 			},
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if answer == YesOption {
 			err = cmd.startPortForwarding(loftPod)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			return cmd.successLocal(password)
+			return cmd.localInstallResult(password), nil
 		}
 	}
 
-	return cmd.successRemote(host, password)
+	return remoteInstallResult(host, password), nil
 }
 
 func (cmd *StartCmd) waitForLoft(password string) (*corev1.Pod, error) {
@@ -423,48 +1135,198 @@ func (cmd *StartCmd) waitForLoft(password string) (*corev1.Pod, error) {
 		return nil, err
 	}
 
+	// wait for the management api to become available, otherwise commands like
+	// loft login will fail right after start returns
+	cmd.Log.StartWait("Waiting until the loft management api is available...")
+	err = clihelper.WaitForLoftAPIService(cmd.RestConfig, cmd.Log)
+	cmd.Log.StopWait()
+	if err != nil {
+		return nil, err
+	}
+
 	// ensure user admin secret is there
 	err = clihelper.EnsureAdminPassword(cmd.KubeClient, cmd.RestConfig, password, cmd.Log)
 	if err != nil {
 		return nil, err
 	}
 
+	if cmd.SetAdminAccessKey {
+		err = cmd.createAdminAccessKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return loftPod, nil
 }
 
-func (cmd *StartCmd) installRemote(email, host string) error {
-	err := clihelper.InstallIngressController(cmd.KubeClient, cmd.Context, cmd.Log)
+// createAdminAccessKey creates an access key for the admin user and prints it or writes it to --admin-access-key-file
+func (cmd *StartCmd) createAdminAccessKey() error {
+	accessKey, err := clihelper.CreateAdminAccessKey(cmd.RestConfig)
 	if err != nil {
-		return errors.Wrap(err, "install ingress controller")
+		return errors.Wrap(err, "create admin access key")
+	}
+
+	if cmd.AdminAccessKeyFile != "" {
+		err = ioutil.WriteFile(cmd.AdminAccessKeyFile, []byte(accessKey), 0600)
+		if err != nil {
+			return errors.Wrap(err, "write admin access key")
+		}
+
+		cmd.Log.Donef("Wrote admin access key to %s", cmd.AdminAccessKeyFile)
+		return nil
 	}
 
+	cmd.Log.Donef("Created admin access key: %s", accessKey)
+	return nil
+}
+
+func (cmd *StartCmd) installRemote(email, host string) (*InstallResult, error) {
 	password := cmd.Password
 	if password == "" {
 		defaultPassword, err := clihelper.GetLoftDefaultPassword(cmd.KubeClient, cmd.Namespace)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		password = defaultPassword
 	}
 
-	err = clihelper.InstallLoftRemote(cmd.Context, cmd.Namespace, password, email, cmd.Version, cmd.Values, host, cmd.Log)
+	err := cmd.installIngressController()
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "install ingress controller")
+	}
+
+	err = clihelper.InstallLoftRemote(cmd.ChartName, cmd.ChartRepo, cmd.Context, cmd.Namespace, password, email, cmd.Version, cmd.Values, host, append(cmd.helmExtraArgs(), cmd.ingressArgs()...), cmd.Log)
+	if err != nil {
+		return nil, err
 	}
 
 	// wait until Loft is ready
 	_, err = cmd.waitForLoft(password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	cmd.Log.Done("Loft pod has successfully started")
-	return cmd.successRemote(host, password)
+	return remoteInstallResult(host, password), nil
+}
+
+// printValuesDebug prints the merged helm values of the loft release for --values-debug. It doesn't
+// ask any questions and doesn't create the loft namespace, so nothing in the cluster is changed
+func (cmd *StartCmd) printValuesDebug() error {
+	password := cmd.Password
+	if password == "" {
+		loftNamespace, err := cmd.KubeClient.CoreV1().Namespaces().Get(context.TODO(), cmd.Namespace, metav1.GetOptions{})
+		if err == nil {
+			password = string(loftNamespace.UID)
+		} else if kerrors.IsNotFound(err) {
+			password = "<uid of namespace " + cmd.Namespace + ">"
+		} else {
+			return err
+		}
+	}
+
+	var helmArgs []string
+	host := strings.TrimSuffix(strings.TrimPrefix(cmd.Host, "https://"), "/")
+	if host == "" {
+		helmArgs = clihelper.LocalHelmArgs(password, cmd.Email, cmd.Version, cmd.Values, cmd.helmExtraArgs())
+	} else {
+		helmArgs = clihelper.RemoteHelmArgs(password, cmd.Email, cmd.Version, cmd.Values, host, append(cmd.helmExtraArgs(), cmd.ingressArgs()...))
+	}
+
+	return clihelper.PrintHelmValues(cmd.ChartName, cmd.ChartRepo, cmd.Context, cmd.Namespace, helmArgs, cmd.Log)
+}
+
+// helmExtraArgs returns the additional helm arguments for installing or upgrading loft
+func (cmd *StartCmd) helmExtraArgs() []string {
+	args := []string{}
+	if cmd.Atomic {
+		args = append(args, "--atomic")
+	}
+	if cmd.StorageClass != "" {
+		args = append(args, "--set", "storage.className="+cmd.StorageClass)
+	}
+	args = append(args, cmd.repoArgs...)
+	if cmd.RepoUsername != "" {
+		args = append(args, "--username", cmd.RepoUsername)
+	}
+	if cmd.RepoPassword != "" {
+		args = append(args, "--password", cmd.RepoPassword)
+	}
+	if cmd.PostRenderer != "" {
+		args = append(args, "--post-renderer", cmd.PostRenderer)
+	}
+	if cmd.Image != "" {
+		args = append(args, "--set-string", "image="+cmd.Image)
+	}
+	if cmd.ImageTag != "" {
+		args = append(args, "--set-string", "tag="+cmd.ImageTag)
+	}
+	if cmd.ImagePullSecret != "" {
+		args = append(args, "--set-string", "imagePullSecrets[0].name="+cmd.ImagePullSecret)
+	}
+
+	for _, resourceFlag := range cmd.resourceFlags() {
+		if resourceFlag.Quantity != "" {
+			args = append(args, "--set-string", resourceFlag.Value+"="+resourceFlag.Quantity)
+		}
+	}
+
+	return args
+}
+
+// resourceFlag is a flag for the resources of the loft container and the helm value it sets
+type resourceFlag struct {
+	Flag     string
+	Value    string
+	Quantity string
+}
+
+func (cmd *StartCmd) resourceFlags() []resourceFlag {
+	return []resourceFlag{
+		{Flag: "cpu-request", Value: "resources.requests.cpu", Quantity: cmd.CPURequest},
+		{Flag: "cpu-limit", Value: "resources.limits.cpu", Quantity: cmd.CPULimit},
+		{Flag: "memory-request", Value: "resources.requests.memory", Quantity: cmd.MemoryRequest},
+		{Flag: "memory-limit", Value: "resources.limits.memory", Quantity: cmd.MemoryLimit},
+	}
+}
+
+// ingressArgs returns the helm arguments for the ingress class and the extra loft ingress annotations
+func (cmd *StartCmd) ingressArgs() []string {
+	args := []string{}
+	if cmd.IngressClass != "" {
+		args = append(args, "--set-string", "ingress.ingressClass="+cmd.IngressClass)
+	}
+	for _, annotation := range cmd.IngressAnnotations {
+		splitted := strings.SplitN(annotation, "=", 2)
+
+		// dots in the key and commas in the value need to be escaped for helm
+		key := strings.ReplaceAll(splitted[0], ".", "\\.")
+		value := strings.ReplaceAll(splitted[1], ",", "\\,")
+		args = append(args, "--set-string", "ingress.annotations."+key+"="+value)
+	}
+
+	return args
+}
+
+func (cmd *StartCmd) installIngressController() error {
+	if cmd.SkipIngressController {
+		cmd.Log.Info("Skipping the ingress-nginx installation, using the existing ingress controller of the cluster")
+		return nil
+	} else if cmd.IngressClass != "" {
+		cmd.Log.Infof("Skipping the ingress-nginx installation, using the existing ingress class %s", cmd.IngressClass)
+		return nil
+	} else if cmd.Offline {
+		cmd.Log.Info("Skipping the ingress-nginx installation in offline mode, please make sure an ingress controller is installed in your cluster")
+		return nil
+	}
+
+	return clihelper.InstallIngressController(cmd.KubeClient, cmd.Context, cmd.Log)
 }
 
 func (cmd *StartCmd) upgradeWithIngress(host string) error {
-	err := clihelper.InstallIngressController(cmd.KubeClient, cmd.Context, cmd.Log)
+	err := cmd.installIngressController()
 	if err != nil {
 		return errors.Wrap(err, "install ingress controller")
 	}
@@ -473,12 +1335,13 @@ func (cmd *StartCmd) upgradeWithIngress(host string) error {
 		"--reuse-values",
 		"--set",
 		"ingress.enabled=true",
-		"--set",
-		"ingress.host=" + host,
 	}
+	extraArgs = append(extraArgs, clihelper.IngressHostValues(host)...)
+	extraArgs = append(extraArgs, cmd.ingressArgs()...)
+	extraArgs = append(extraArgs, cmd.helmExtraArgs()...)
 
 	// upgrade loft
-	err = clihelper.UpgradeLoft(cmd.Context, cmd.Namespace, extraArgs, cmd.Log)
+	err = clihelper.UpgradeLoft(cmd.ChartName, cmd.ChartRepo, cmd.Context, cmd.Namespace, extraArgs, cmd.Log)
 	if err != nil {
 		return err
 	}
@@ -486,57 +1349,58 @@ func (cmd *StartCmd) upgradeWithIngress(host string) error {
 	return nil
 }
 
-func (cmd *StartCmd) installLocal(email string) error {
+func (cmd *StartCmd) installLocal(email string) (*InstallResult, error) {
 	password := cmd.Password
 	if password == "" {
 		defaultPassword, err := clihelper.GetLoftDefaultPassword(cmd.KubeClient, cmd.Namespace)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		password = defaultPassword
 	}
 
-	err := clihelper.InstallLoftLocally(cmd.Context, cmd.Namespace, password, email, cmd.Version, cmd.Values, cmd.Log)
+	err := clihelper.InstallLoftLocally(cmd.ChartName, cmd.ChartRepo, cmd.Context, cmd.Namespace, password, email, cmd.Version, cmd.Values, cmd.helmExtraArgs(), cmd.Log)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// wait until Loft is ready
 	loftPod, err := cmd.waitForLoft(password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = cmd.startPortForwarding(loftPod)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	
-	return cmd.successLocal(password)
+	return cmd.localInstallResult(password), nil
 }
 
 func (cmd *StartCmd) startPortForwarding(loftPod *corev1.Pod) error {
-	stopChan, err := clihelper.StartPortForwarding(cmd.RestConfig, cmd.KubeClient, loftPod, cmd.LocalPort, cmd.Log)
+	if cmd.PrintCommand {
+		cmd.Log.WriteString("\nPort-forward command: " + cmd.portForwardCommand(loftPod) + "\n\n")
+	}
+
+	stopChan, localPort, err := clihelper.StartPortForwarding(cmd.RestConfig, cmd.KubeClient, loftPod, cmd.LocalPort, cmd.Log)
 	if err != nil {
-		return err
+		return fmt.Errorf("error starting port forwarding: %v. You can try to forward the port manually with: %s", err, cmd.portForwardCommand(loftPod))
 	}
+
+	// remember the chosen port if --local-port is 0, so a restart reuses it
+	cmd.LocalPort = localPort
 	go cmd.restartPortForwarding(stopChan)
 
 	// wait until loft is reachable at the given url
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	cmd.Log.Infof("Waiting until loft is reachable at https://localhost:%s", cmd.LocalPort)
-	err = wait.PollImmediate(time.Second, time.Minute*10, func() (bool, error) {
+	httpClient := clihelper.NewLoftProbeClient()
+	err = util.WaitForCondition(context.TODO(), time.Second, time.Minute*10, "loft to become reachable at https://localhost:"+cmd.LocalPort, cmd.Log, func() (bool, error) {
 		resp, err := httpClient.Get("https://localhost:" + cmd.LocalPort + "/version")
 		if err != nil {
 			return false, nil
 		}
+		defer resp.Body.Close()
 
 		return resp.StatusCode == http.StatusOK, nil
 	})
@@ -547,6 +1411,16 @@ func (cmd *StartCmd) startPortForwarding(loftPod *corev1.Pod) error {
 	return nil 
 }
 
+// portForwardCommand returns the kubectl command that forwards the same port as loft start does
+func (cmd *StartCmd) portForwardCommand(loftPod *corev1.Pod) string {
+	localPort := cmd.LocalPort
+	if localPort == "0" {
+		localPort = ""
+	}
+
+	return fmt.Sprintf("kubectl port-forward --context %s --namespace %s pod/%s %s:443", cmd.Context, loftPod.Namespace, loftPod.Name, localPort)
+}
+
 func (cmd *StartCmd) restartPortForwarding(stopChan chan struct{}) {
 	for {
 		<- stopChan
@@ -561,7 +1435,7 @@ func (cmd *StartCmd) restartPortForwarding(stopChan chan struct{}) {
 		}
 
 		// restart port forwarding
-		stopChan, err = clihelper.StartPortForwarding(cmd.RestConfig, cmd.KubeClient, loftPod, cmd.LocalPort, cmd.Log)
+		stopChan, _, err = clihelper.StartPortForwarding(cmd.RestConfig, cmd.KubeClient, loftPod, cmd.LocalPort, cmd.Log)
 		if err != nil {
 			cmd.Log.Fatalf("Error starting port forwarding: %v", err)
 		}
@@ -570,34 +1444,148 @@ func (cmd *StartCmd) restartPortForwarding(stopChan chan struct{}) {
 	}
 }
 
+// success prints how to reach loft after the installation and, for a local installation,
+// keeps port-forwarding running
+func (cmd *StartCmd) success(result *InstallResult) error {
+	if result == nil {
+		return nil
+	}
+
+	err := cmd.applyExtraManifests()
+	if err != nil {
+		return err
+	}
+
+	if result.Mode == InstallModeLocal {
+		return cmd.successLocal(result.Password)
+	}
+
+	return cmd.successRemote(result.Host, result.Password)
+}
+
+// applyExtraManifests applies the manifests of --extra-manifest now that loft is ready
+func (cmd *StartCmd) applyExtraManifests() error {
+	for _, manifest := range cmd.ExtraManifests {
+		applied, err := clihelper.ApplyManifests(cmd.RestConfig, manifest)
+		for _, name := range applied {
+			cmd.Log.Donef("Applied %s", name)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "apply extra manifest %s", manifest)
+		}
+	}
+
+	return nil
+}
+
+func (cmd *StartCmd) localInstallResult(password string) *InstallResult {
+	return &InstallResult{
+		Host:     "localhost:" + cmd.LocalPort,
+		Password: password,
+		Mode:     InstallModeLocal,
+		URL:      "https://localhost:" + cmd.LocalPort,
+	}
+}
+
+func remoteInstallResult(host, password string) *InstallResult {
+	return &InstallResult{
+		Host:     host,
+		Password: password,
+		Mode:     InstallModeRemote,
+		URL:      "https://" + host,
+	}
+}
+
 func (cmd *StartCmd) successRemote(host string, password string) error {
-	ready, err := clihelper.IsLoftReachable(host)
+	loftVersion, err := clihelper.GetLoftVersion(host)
 	if err != nil {
 		return err
-	} else if ready {
-		printhelper.PrintSuccessMessageRemoteInstall(host, password, cmd.Log)
+	} else if loftVersion != "" {
+		clihelper.PrintLoftCertificateWarnings(host, cmd.Log)
+		printhelper.PrintSuccessMessageRemoteInstall(host, password, loftVersion, cmd.Log)
 		return nil
 	}
 
 	// Print DNS Configuration
-	printhelper.PrintDNSConfiguration(host, cmd.Log)
+	hostname, _ := clihelper.SplitHostPath(host)
+	if cmd.WaitForLB {
+		address, err := clihelper.WaitForIngressControllerAddress(cmd.KubeClient, time.Minute*15, cmd.Log)
+		if err != nil {
+			cmd.Log.Warnf("Couldn't retrieve the external address of the ingress-nginx load balancer: %v", err)
+			printhelper.PrintDNSConfiguration(hostname, cmd.Log)
+		} else {
+			printhelper.PrintDNSConfigurationForAddress(hostname, address, cmd.Log)
+		}
+	} else {
+		printhelper.PrintDNSConfiguration(hostname, cmd.Log)
+	}
+
+	resolved := ""
+	if cmd.DNSCheck {
+		resolved = cmd.reportDNSResolution(host, resolved, true)
+	}
+
+	lastStatus := ""
+	loftVersion = ""
+	err = util.WaitForConditionWithBackoff(context.TODO(), time.Second*5, time.Minute, time.Hour*24, "you to configure DNS, so loft can be reached on https://"+host, cmd.Log, func() (bool, bool, error) {
+		// poll more often again as soon as something changes, e.g. the dns record was just created
+		changed := false
+		if cmd.DNSCheck {
+			lastResolved := resolved
+			resolved = cmd.reportDNSResolution(host, resolved, false)
+			changed = resolved != lastResolved
+		}
 
-	cmd.Log.StartWait("Waiting for you to configure DNS, so loft can be reached on https://" + host)
-	err = wait.PollImmediate(time.Second*5, time.Hour*24, func() (bool, error) {
-		return clihelper.IsLoftReachable(host)
+		// tell the user when the reason loft isn't reachable changes, e.g. from a dns failure to loft starting up
+		status := clihelper.CheckLoftReachability(host)
+		if status.Reachability != clihelper.LoftReachable && status.String() != lastStatus {
+			cmd.Log.Infof("Loft is not reachable yet: %s", status.String())
+			changed = changed || lastStatus != ""
+		}
+		lastStatus = status.String()
+		loftVersion = status.Version
+		return status.Reachability == clihelper.LoftReachable, changed, nil
 	})
-	cmd.Log.StopWait()
 	if err != nil {
 		return err
 	}
 
 	cmd.Log.Done("loft is reachable at https://" + host)
-	printhelper.PrintSuccessMessageRemoteInstall(host, password, cmd.Log)
+	clihelper.PrintLoftCertificateWarnings(host, cmd.Log)
+	printhelper.PrintSuccessMessageRemoteInstall(host, password, loftVersion, cmd.Log)
 	return nil
 }
 
+// reportDNSResolution looks up the given host and reports the result if it is the first lookup or
+// if the result has changed since the last lookup
+func (cmd *StartCmd) reportDNSResolution(host, lastResolved string, first bool) string {
+	resolved := ""
+	host, _ = clihelper.SplitHostPath(host)
+	addresses, err := net.LookupHost(host)
+	if err == nil && len(addresses) > 0 {
+		sort.Strings(addresses)
+		resolved = strings.Join(addresses, ", ")
+	}
+	if first == false && resolved == lastResolved {
+		return resolved
+	}
+
+	if resolved == "" {
+		cmd.Log.Infof("Host %s currently does not resolve", host)
+	} else {
+		cmd.Log.Infof("Host %s resolves to %s", host, resolved)
+	}
+
+	return resolved
+}
+
 func (cmd *StartCmd) successLocal(password string) error {
-	printhelper.PrintSuccessMessageLocalInstall(password, cmd.LocalPort, cmd.Log)
+	loftVersion, err := clihelper.GetLoftVersion("localhost:" + cmd.LocalPort)
+	if err != nil {
+		cmd.Log.Warnf("Couldn't retrieve the installed loft version: %v", err)
+	}
+
+	printhelper.PrintSuccessMessageLocalInstall(password, cmd.LocalPort, loftVersion, cmd.Log)
 
 	blockChan := make(chan bool)
 	<-blockChan
@@ -9,7 +9,9 @@ import (
 
 // UpgradeCmd is a struct that defines a command call for "upgrade"
 type UpgradeCmd struct{
-	Version string
+	Version      string
+	DownloadOnly bool
+	Dir          string
 	
 	log log.Logger
 }
@@ -28,17 +30,34 @@ func NewUpgradeCmd() *cobra.Command {
 #################### loft upgrade #####################
 #######################################################
 Upgrades the loft CLI to the newest version
+
+Example:
+loft upgrade
+loft upgrade --version 1.10.0
+loft upgrade --download-only --dir /tmp/loft
 #######################################################`,
 		Args: cobra.NoArgs,
 		RunE: cmd.Run,
 	}
 
 	upgradeCmd.Flags().StringVar(&cmd.Version, "version", "", "The version to update loft to. Defaults to the latest stable version available")
+	upgradeCmd.Flags().BoolVar(&cmd.DownloadOnly, "download-only", false, "If true, only downloads the new binary into --dir without replacing the current one")
+	upgradeCmd.Flags().StringVar(&cmd.Dir, "dir", ".", "The directory to download the new binary to with --download-only")
 	return upgradeCmd
 }
 
 // Run executes the command logic
 func (cmd *UpgradeCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	if cmd.DownloadOnly {
+		binaryPath, err := upgrade.Download(cmd.Version, cmd.Dir, cmd.log)
+		if err != nil {
+			return errors.Errorf("Couldn't download: %v", err)
+		}
+
+		cmd.log.Donef("Successfully downloaded loft to %s", binaryPath)
+		return nil
+	}
+
 	err := upgrade.Upgrade(cmd.Version, cmd.log)
 	if err != nil {
 		return errors.Errorf("Couldn't upgrade: %v", err)
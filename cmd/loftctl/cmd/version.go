@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/spf13/cobra"
+)
+
+// VersionCmd holds the cmd flags
+type VersionCmd struct {
+	Output string
+
+	log log.Logger
+}
+
+// NewVersionCmd creates a new version command
+func NewVersionCmd() *cobra.Command {
+	cmd := &VersionCmd{
+		log: log.GetInstance(),
+	}
+
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Prints the version and build information of the loft CLI",
+		Long: `
+#######################################################
+#################### loft version #####################
+#######################################################
+Prints the version and build information of the loft CLI
+
+Example:
+loft version
+loft version -o json
+#######################################################`,
+		Args: cobra.NoArgs,
+		RunE: cmd.Run,
+	}
+
+	versionCmd.Flags().StringVarP(&cmd.Output, "output", "o", "", "The output format to use. Valid options are: json")
+	return versionCmd
+}
+
+// Run executes the command logic
+func (cmd *VersionCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	if cmd.Output != "" && cmd.Output != "json" {
+		return fmt.Errorf("unsupported output format %s, valid options are: json", cmd.Output)
+	}
+
+	buildInfo := upgrade.GetBuildInfo()
+	if cmd.Output == "json" {
+		out, err := json.MarshalIndent(buildInfo, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		cmd.log.WriteString(string(out) + "\n")
+		return nil
+	}
+
+	version := buildInfo.Version
+	if version == "" {
+		version = "dev"
+	}
+
+	cmd.log.WriteString(fmt.Sprintf("Version:    %s\n", version))
+	if buildInfo.Commit != "" {
+		cmd.log.WriteString(fmt.Sprintf("Commit:     %s\n", buildInfo.Commit))
+	}
+	if buildInfo.BuildDate != "" {
+		cmd.log.WriteString(fmt.Sprintf("Build date: %s\n", buildInfo.BuildDate))
+	}
+	cmd.log.WriteString(fmt.Sprintf("Go version: %s\n", buildInfo.GoVersion))
+	cmd.log.WriteString(fmt.Sprintf("Platform:   %s\n", buildInfo.Platform))
+	return nil
+}
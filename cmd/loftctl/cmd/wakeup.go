@@ -3,24 +3,38 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"sort"
+	"sync"
 	"time"
 
+	clusterv1 "github.com/loft-sh/agentapi/pkg/apis/loft/cluster/v1"
 	"github.com/loft-sh/loftctl/cmd/loftctl/flags"
 	"github.com/loft-sh/loftctl/pkg/client"
 	"github.com/loft-sh/loftctl/pkg/client/helper"
+	"github.com/loft-sh/loftctl/pkg/kube"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 // WakeUpCmd holds the cmd flags
 type WakeUpCmd struct {
 	*flags.GlobalFlags
 
-	Cluster string
-	Log     log.Logger
+	Cluster    string
+	Namespace  string
+	ClusterAll bool
+	NoWait     bool
+	DryRun     bool
+	Output     string
+
+	WaitForPhase string
+
+	Log log.Logger
 }
 
 // NewWakeUpCmd creates a new command
@@ -39,6 +53,11 @@ wakeup resumes a sleeping space
 Example:
 loft wakeup myspace
 loft wakeup myspace --cluster mycluster
+loft wakeup myspace --namespace myspace-ns
+loft wakeup myspace --wait-for-phase Active
+loft wakeup --cluster-all
+loft wakeup --cluster-all --dry-run
+loft list spaces -o name | xargs -n1 loft wakeup --no-wait -o name
 #######################################################
 	`
 	if upgrade.IsPlugin == "true" {
@@ -51,6 +70,11 @@ wakeup resumes a sleeping space
 Example:
 devspace wakeup myspace
 devspace wakeup myspace --cluster mycluster
+devspace wakeup myspace --namespace myspace-ns
+devspace wakeup myspace --wait-for-phase Active
+devspace wakeup --cluster-all
+devspace wakeup --cluster-all --dry-run
+devspace list spaces -o name | xargs -n1 devspace wakeup --no-wait -o name
 #######################################################
 	`
 	}
@@ -66,22 +90,48 @@ devspace wakeup myspace --cluster mycluster
 	}
 
 	c.Flags().StringVar(&cmd.Cluster, "cluster", "", "The cluster to use")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "", "The namespace of the space sleep mode config, if it differs from the space name")
+	c.Flags().BoolVar(&cmd.ClusterAll, "cluster-all", false, "If enabled, wakes up all sleeping spaces in all clusters you have access to")
+	c.Flags().BoolVar(&cmd.NoWait, "no-wait", false, "If enabled, does not wait until the space has woken up")
+	c.Flags().StringVar(&cmd.WaitForPhase, "wait-for-phase", "", "If set, waits until the woken up space has reached this status phase, e.g. Active")
+	c.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "If enabled, only prints which spaces would be woken up without waking them up")
+	c.Flags().StringVarP(&cmd.Output, "output", "o", "", "The output format to use. Valid options are: name (only prints the names of the woken up spaces)")
 	return c
 }
 
 // Run executes the functionality
 func (cmd *WakeUpCmd) Run(cobraCmd *cobra.Command, args []string) error {
+	// in name output mode we only print the names of the woken up spaces
+	out := cmd.Log
+	if cmd.Output == "name" {
+		cmd.Log = log.Discard
+	} else if cmd.Output != "" {
+		return fmt.Errorf("unsupported output format %s, valid options are: name", cmd.Output)
+	}
+
 	baseClient, err := client.NewClientFromPath(cmd.Config)
 	if err != nil {
 		return err
 	}
 
+	if cmd.ClusterAll {
+		if len(args) > 0 || cmd.Cluster != "" || cmd.Namespace != "" {
+			return fmt.Errorf("--cluster-all cannot be used together with a space name, --cluster or --namespace")
+		}
+
+		if cmd.WaitForPhase != "" {
+			return fmt.Errorf("--wait-for-phase cannot be used together with --cluster-all")
+		}
+
+		return cmd.wakeUpAllClusters(baseClient, out)
+	}
+
 	spaceName := ""
 	if len(args) > 0 {
 		spaceName = args[0]
 	}
 
-	spaceName, clusterName, err := helper.SelectSpaceAndClusterName(baseClient, spaceName, cmd.Cluster, cmd.Log)
+	spaceName, clusterName, err := helper.SelectSpaceAndClusterNameFuzzy(baseClient, spaceName, cmd.Cluster, cmd.Log)
 	if err != nil {
 		return err
 	}
@@ -91,36 +141,217 @@ func (cmd *WakeUpCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(spaceName).List(context.TODO(), metav1.ListOptions{})
+	namespace := spaceName
+	if cmd.Namespace != "" {
+		namespace = cmd.Namespace
+	}
+
+	if cmd.DryRun {
+		return cmd.printWakeUpDryRun(clusterClient, spaceName, namespace, clusterName, out)
+	}
+
+	if cmd.Log.GetVerbosity() >= log.VerbosityDetailed {
+		cmd.Log.Debugf("Waking up space %s in cluster %s", spaceName, clusterName)
+	}
+
+	// wait for sleeping
+	cmd.Log.StartWait("Wait until space wakes up")
+	defer cmd.Log.StopWait()
+	err = wakeUpSpace(clusterClient, namespace, !cmd.NoWait)
+	if err != nil {
+		return err
+	}
+	cmd.Log.StopWait()
+
+	if cmd.WaitForPhase != "" {
+		err = helper.WaitForSpacePhase(clusterClient, spaceName, cmd.WaitForPhase, time.Minute*5, cmd.Log)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.Output == "name" {
+		out.WriteString(spaceName + "\n")
+		return nil
+	}
+
+	cmd.Log.Donef("Successfully woken up space %s", spaceName)
+	return nil
+}
+
+// printWakeUpDryRun prints the current sleep state of the space and what wakeup would do
+func (cmd *WakeUpCmd) printWakeUpDryRun(clusterClient kube.Interface, spaceName, namespace, clusterName string, out log.Logger) error {
+	configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	} else if len(configs.Items) == 0 || configs.Items[0].Status.SleepingSince == 0 {
+		cmd.Log.Infof("Space %s in cluster %s is not sleeping, wakeup would only reset its last activity", spaceName, clusterName)
+	} else {
+		cmd.Log.Infof("Space %s in cluster %s is sleeping since %s", spaceName, clusterName, duration.HumanDuration(time.Now().Sub(time.Unix(configs.Items[0].Status.SleepingSince, 0))))
+	}
+
+	if cmd.Output == "name" {
+		out.WriteString(spaceName + "\n")
+		return nil
+	}
+
+	cmd.Log.Infof("Would wake up space %s in cluster %s", spaceName, clusterName)
+	return nil
+}
+
+type wakeUpResult struct {
+	Space   string
+	Cluster string
+	Err     error
+}
+
+func (cmd *WakeUpCmd) wakeUpAllClusters(baseClient client.Client, out log.Logger) error {
+	clusters, err := helper.ListClusterAccounts(baseClient)
+	if err != nil {
+		return err
+	}
+
+	if cmd.DryRun {
+		cmd.Log.StartWait("Looking for sleeping spaces in all clusters")
+	} else {
+		cmd.Log.StartWait("Waking up sleeping spaces in all clusters")
+	}
+	resultsMutex := sync.Mutex{}
+	results := []wakeUpResult{}
+	errs := []error{}
+	waitGroup := sync.WaitGroup{}
+	for _, cluster := range clusters {
+		waitGroup.Add(1)
+		go func(clusterName string) {
+			defer waitGroup.Done()
+
+			clusterResults, err := wakeUpCluster(baseClient, clusterName, !cmd.NoWait, cmd.DryRun)
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("cluster %s: %v", clusterName, err))
+			}
+			results = append(results, clusterResults...)
+		}(cluster.Cluster.Name)
+	}
+	waitGroup.Wait()
+	cmd.Log.StopWait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Cluster == results[j].Cluster {
+			return results[i].Space < results[j].Space
+		}
+
+		return results[i].Cluster < results[j].Cluster
+	})
+
+	header := []string{
+		"Space",
+		"Cluster",
+		"Result",
+	}
+	values := [][]string{}
+	for _, result := range results {
+		status := "Woken up"
+		if cmd.DryRun {
+			status = "Would wake up"
+		}
+		if result.Err != nil {
+			status = "Error: " + result.Err.Error()
+			errs = append(errs, fmt.Errorf("space %s in cluster %s: %v", result.Space, result.Cluster, result.Err))
+		}
+
+		values = append(values, []string{
+			result.Space,
+			result.Cluster,
+			status,
+		})
+	}
+
+	if cmd.Output == "name" {
+		for _, result := range results {
+			if result.Err == nil {
+				out.WriteString(result.Space + "\n")
+			}
+		}
+	} else {
+		log.PrintTable(cmd.Log, header, values)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func wakeUpCluster(baseClient client.Client, clusterName string, waitForWakeUp, dryRun bool) ([]wakeUpResult, error) {
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	spaces, err := clusterClient.Kiosk().TenancyV1alpha1().Spaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []wakeUpResult{}
+	for _, space := range spaces.Items {
+		configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(space.Name).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			results = append(results, wakeUpResult{Space: space.Name, Cluster: clusterName, Err: err})
+			continue
+		} else if len(configs.Items) == 0 || configs.Items[0].Status.SleepingSince == 0 {
+			continue
+		} else if dryRun {
+			results = append(results, wakeUpResult{Space: space.Name, Cluster: clusterName})
+			continue
+		}
+
+		results = append(results, wakeUpResult{
+			Space:   space.Name,
+			Cluster: clusterName,
+			Err:     wakeUpSpace(clusterClient, space.Name, waitForWakeUp),
+		})
+	}
+
+	return results, nil
+}
+
+// wakeUpSpace wakes up the space whose sleep mode config is in the given namespace
+func wakeUpSpace(clusterClient kube.Interface, namespace string, waitForWakeUp bool) error {
+	sleepModeConfig, err := getSleepModeConfig(clusterClient, namespace)
 	if err != nil {
 		return err
 	}
 
-	sleepModeConfig := &configs.Items[0]
 	sleepModeConfig.Spec.ForceSleep = false
 	sleepModeConfig.Spec.ForceSleepDuration = nil
 	sleepModeConfig.Status.LastActivity = time.Now().Unix()
 
-	sleepModeConfig, err = clusterClient.Agent().ClusterV1().SleepModeConfigs(spaceName).Create(context.TODO(), sleepModeConfig, metav1.CreateOptions{})
+	_, err = clusterClient.Agent().ClusterV1().SleepModeConfigs(namespace).Create(context.TODO(), sleepModeConfig, metav1.CreateOptions{})
 	if err != nil {
 		return err
+	} else if waitForWakeUp == false {
+		return nil
 	}
 
-	// wait for sleeping
-	cmd.Log.StartWait("Wait until space wakes up")
-	defer cmd.Log.StopWait()
-	err = wait.Poll(time.Second, time.Minute, func() (bool, error) {
-		configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(spaceName).List(context.TODO(), metav1.ListOptions{})
+	// wait for wake up, the progress is shown by the caller
+	return util.WaitForCondition(context.TODO(), time.Second, time.Minute, "space "+namespace+" to wake up", log.Discard, func() (bool, error) {
+		sleepModeConfig, err := getSleepModeConfig(clusterClient, namespace)
 		if err != nil {
 			return false, err
 		}
 
-		return configs.Items[0].Status.SleepingSince == 0, nil
+		return sleepModeConfig.Status.SleepingSince == 0, nil
 	})
+}
+
+// getSleepModeConfig returns the sleep mode config in the given namespace
+func getSleepModeConfig(clusterClient kube.Interface, namespace string) (*clusterv1.SleepModeConfig, error) {
+	configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		return fmt.Errorf("error waiting for space to wake up: %v", err)
+		return nil, err
+	} else if len(configs.Items) == 0 {
+		return nil, fmt.Errorf("no sleep mode config in namespace %s", namespace)
 	}
 
-	cmd.Log.Donef("Successfully woken up space %s", spaceName)
-	return nil
+	return &configs.Items[0], nil
 }
@@ -3,13 +3,22 @@ package flags
 import (
 	"github.com/loft-sh/loftctl/pkg/client"
 	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 // GlobalFlags is the flags that contains the global flags
 type GlobalFlags struct {
-	Silent bool
-	Debug  bool
-	Config string
+	Silent    bool
+	Debug     bool
+	Verbosity int
+	Config    string
+	NoBanner  bool
+	AssumeYes bool
+	Plain     bool
+
+	As       string
+	AsGroups []string
 }
 
 // SetGlobalFlags applies the global flags
@@ -18,7 +27,23 @@ func SetGlobalFlags(flags *flag.FlagSet) *GlobalFlags {
 
 	flags.StringVar(&globalFlags.Config, "config", client.DefaultCacheConfig, "The loft config to use (will be created if it does not exist)")
 	flags.BoolVar(&globalFlags.Debug, "debug", false, "Prints the stack trace if an error occurs")
+	flags.CountVar(&globalFlags.Verbosity, "verbose", "Increases the log verbosity, can be repeated (--verbose --verbose logs kubernetes api requests, three times enables client-go request logging)")
+	flags.StringVar(&globalFlags.As, "as", "", "Username to impersonate for the kubernetes api requests, helm and kubectl calls against the cluster loft is installed in")
+	flags.StringArrayVar(&globalFlags.AsGroups, "as-group", []string{}, "Group to impersonate for the kubernetes api requests, can be repeated to specify multiple groups")
+	flags.BoolVar(&globalFlags.NoBanner, "no-banner", false, "Prints messages without the decorative banners")
+	flags.BoolVar(&globalFlags.AssumeYes, "yes", false, "Skips the confirmation of destructive operations like resetting loft or deleting spaces. Can also be set via LOFT_ASSUME_YES=true")
+	flags.BoolVar(&globalFlags.Plain, "plain", false, "Prints progress as plain lines with the elapsed time instead of an animated spinner and disables colors, e.g. for log files")
 	flags.BoolVar(&globalFlags.Silent, "silent", false, "Run in silent mode and prevents any devspace log output except panics & fatals")
 
 	return globalFlags
 }
+
+// ConfigOverrides returns the kube config overrides that apply the impersonation flags
+func (g *GlobalFlags) ConfigOverrides() *clientcmd.ConfigOverrides {
+	return &clientcmd.ConfigOverrides{
+		AuthInfo: clientcmdapi.AuthInfo{
+			Impersonate:       g.As,
+			ImpersonateGroups: g.AsGroups,
+		},
+	}
+}
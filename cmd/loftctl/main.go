@@ -8,10 +8,15 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+// version and build metadata are set at build time, e.g.
+// go build -ldflags "-X main.version=1.2.3 -X main.commit=$(git rev-parse HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/loftctl
 var version string
+var commit string
+var buildDate string
 
 func main() {
 	upgrade.SetVersion(version)
+	upgrade.SetBuildInfo(commit, buildDate)
 
 	cmd.Execute()
 	os.Exit(0)
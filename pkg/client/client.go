@@ -19,6 +19,7 @@ import (
 
 	"github.com/loft-sh/loftctl/pkg/kube"
 	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	"github.com/skratchdot/open-golang/open"
@@ -32,6 +33,13 @@ var cacheFolder = ".loft"
 // DefaultCacheConfig is the path to the config
 var DefaultCacheConfig = "config.json"
 
+const (
+	// EnvServer overrides the loft host of the config if set
+	EnvServer = "LOFT_SERVER"
+	// EnvAccessKey overrides the access key of the config if set
+	EnvAccessKey = "LOFT_ACCESS_KEY"
+)
+
 const (
 	LoginPath     = "%s/login?cli=true"
 	RedirectPath  = "%s/spaces"
@@ -45,6 +53,12 @@ func init() {
 	DefaultCacheConfig = filepath.Join(cacheFolder, DefaultCacheConfig)
 }
 
+// CacheDir returns the directory loftctl caches data in for the given config path. It is
+// always safe to delete
+func CacheDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "cache")
+}
+
 type Client interface {
 	Management() (kube.Interface, error)
 	ManagementConfig() (*rest.Config, error)
@@ -56,11 +70,15 @@ type Client interface {
 	VirtualClusterConfig(cluster, namespace, virtualCluster string) (*rest.Config, error)
 
 	Login(host string, insecure bool, log log.Logger) error
+	LoginWithSSO(host string, insecure bool, log log.Logger) error
 	LoginWithAccessKey(host, accessKey string, insecure bool) error
 
 	Config() *Config
 	DirectClusterEndpointToken(forceRefresh bool) (string, error)
 	Save() error
+
+	SetContext(name string, context *Context) error
+	UseContext(name string) error
 }
 
 func NewClientFromPath(path string) (Client, error) {
@@ -70,7 +88,7 @@ func NewClientFromPath(path string) (Client, error) {
 
 	err := c.initConfig()
 	if err != nil {
-		return nil, err
+		return nil, wrapError(ErrInvalidConfig, err)
 	}
 
 	return c, nil
@@ -80,6 +98,20 @@ type client struct {
 	configOnce sync.Once
 	configPath string
 	config     *Config
+
+	// the config file values that are replaced while LOFT_SERVER or LOFT_ACCESS_KEY are set
+	envOverrides                            bool
+	envHost                                 string
+	envAccessKey                            string
+	fileHost                                string
+	fileAccessKey                           string
+	fileDirectClusterEndpointToken          string
+	fileDirectClusterEndpointTokenRequested *metav1.Time
+
+	accessKeyMutex sync.Mutex
+
+	clusterClientsMutex sync.Mutex
+	clusterClients      map[string]kube.Interface
 }
 
 func (c *client) initConfig() error {
@@ -92,30 +124,75 @@ func (c *client) initConfig() error {
 		}
 
 		// load the config or create new one if not found
-		content, err := ioutil.ReadFile(c.configPath)
+		config, err := loadConfig(c.configPath)
 		if err != nil {
-			if os.IsNotExist(err) {
-				c.config = NewConfig()
+			if os.IsNotExist(err) == false {
+				retErr = err
 				return
 			}
 
-			retErr = err
-			return
-		}
-
-		config := &Config{}
-		err = json.Unmarshal(content, config)
-		if err != nil {
-			retErr = err
-			return
+			config = NewConfig()
 		}
 
 		c.config = config
+		c.setConfig(config)
 	})
 
 	return retErr
 }
 
+// setConfig replaces the loaded config with the given config file contents and applies the
+// environment overrides. The config is updated in place, so callers of Config() see the change.
+func (c *client) setConfig(config *Config) {
+	c.fileHost, c.fileAccessKey = config.Host, config.AccessKey
+	c.fileDirectClusterEndpointToken, c.fileDirectClusterEndpointTokenRequested = config.DirectClusterEndpointToken, config.DirectClusterEndpointTokenRequested
+	c.envOverrides = applyEnvironmentOverrides(config)
+	c.envHost, c.envAccessKey = config.Host, config.AccessKey
+	if c.config != config {
+		*c.config = *config
+	}
+}
+
+// applyEnvironmentOverrides replaces the host and access key of the config with the values of
+// LOFT_SERVER and LOFT_ACCESS_KEY, so commands can be used without a config file, and returns
+// if any value was overridden. The access key of the config file is only kept if LOFT_SERVER
+// points to the same loft, so it is never sent to a host it wasn't issued by.
+func applyEnvironmentOverrides(config *Config) bool {
+	host := strings.TrimSuffix(os.Getenv(EnvServer), "/")
+	accessKey := os.Getenv(EnvAccessKey)
+	if host == "" && accessKey == "" {
+		return false
+	}
+
+	if host != "" && host != config.Host {
+		config.Host = host
+		config.AccessKey = ""
+	}
+	if accessKey != "" {
+		config.AccessKey = accessKey
+	}
+
+	// the cached cluster endpoint token might belong to another loft or user
+	config.DirectClusterEndpointToken = ""
+	config.DirectClusterEndpointTokenRequested = nil
+	return true
+}
+
+func loadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	err = json.Unmarshal(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
 func (c *client) DirectClusterEndpointToken(forceRefresh bool) (string, error) {
 	if c.config == nil {
 		return "", errors.New("no config loaded")
@@ -139,7 +216,7 @@ func (c *client) DirectClusterEndpointToken(forceRefresh bool) (string, error) {
 			return c.config.DirectClusterEndpointToken, nil
 		}
 
-		return "", err
+		return "", ClassifyError(err)
 	} else if clusterGatewayToken.Status.Token == "" {
 		return "", errors.New("retrieved an empty token")
 	}
@@ -165,7 +242,18 @@ func (c *client) Save() error {
 		c.config.TypeMeta.APIVersion = "storage.loft.sh/v1"
 	}
 
-	out, err := json.Marshal(c.config)
+	// don't persist the values from the environment or anything that was retrieved with them
+	config := *c.config
+	if c.envOverrides && config.Host == c.envHost {
+		config.Host = c.fileHost
+		config.DirectClusterEndpointToken = c.fileDirectClusterEndpointToken
+		config.DirectClusterEndpointTokenRequested = c.fileDirectClusterEndpointTokenRequested
+	}
+	if c.envOverrides && config.AccessKey == c.envAccessKey {
+		config.AccessKey = c.fileAccessKey
+	}
+
+	out, err := json.Marshal(&config)
 	if err != nil {
 		return err
 	}
@@ -173,6 +261,49 @@ func (c *client) Save() error {
 	return ioutil.WriteFile(c.configPath, out, 0666)
 }
 
+// SetContext stores the given loft context under the given name
+func (c *client) SetContext(name string, context *Context) error {
+	if c.config == nil {
+		return errors.New("no config loaded")
+	} else if name == "" {
+		return errors.New("context name is empty")
+	}
+	if c.config.Contexts == nil {
+		c.config.Contexts = map[string]*Context{}
+	}
+
+	c.config.Contexts[name] = context
+	if c.config.CurrentContext == name {
+		return c.UseContext(name)
+	}
+
+	return c.Save()
+}
+
+// UseContext switches the loft instance that is used to the context with the given name
+func (c *client) UseContext(name string) error {
+	if c.config == nil {
+		return errors.New("no config loaded")
+	}
+
+	context, ok := c.config.Contexts[name]
+	if !ok || context == nil {
+		return fmt.Errorf("loft context %s does not exist", name)
+	}
+
+	if c.config.Host != context.Host || c.config.AccessKey != context.AccessKey {
+		c.config.DirectClusterEndpointToken = ""
+		c.config.DirectClusterEndpointTokenRequested = nil
+		c.resetClusterClients()
+	}
+
+	c.config.Host = context.Host
+	c.config.Insecure = context.Insecure
+	c.config.AccessKey = context.AccessKey
+	c.config.CurrentContext = name
+	return c.Save()
+}
+
 func (c *client) ManagementConfig() (*rest.Config, error) {
 	return c.restConfig("/kubernetes/management")
 }
@@ -183,20 +314,51 @@ func (c *client) Management() (kube.Interface, error) {
 		return nil, err
 	}
 
-	return kube.NewForConfig(restConfig)
+	managementClient, err := kube.NewForConfig(restConfig)
+	if err != nil {
+		return nil, wrapError(ErrInvalidConfig, err)
+	}
+
+	return managementClient, nil
 }
 
 func (c *client) ClusterConfig(cluster string) (*rest.Config, error) {
 	return c.restConfig("/kubernetes/cluster/" + cluster)
 }
 
+// Cluster returns a client for the given cluster. Clients are cached per cluster name,
+// so repeated calls reuse the same client and its connections.
 func (c *client) Cluster(cluster string) (kube.Interface, error) {
+	c.clusterClientsMutex.Lock()
+	defer c.clusterClientsMutex.Unlock()
+
+	if clusterClient, ok := c.clusterClients[cluster]; ok {
+		return clusterClient, nil
+	}
+
 	restConfig, err := c.ClusterConfig(cluster)
 	if err != nil {
 		return nil, err
 	}
 
-	return kube.NewForConfig(restConfig)
+	clusterClient, err := kube.NewForConfig(restConfig)
+	if err != nil {
+		return nil, wrapError(ErrInvalidConfig, err)
+	}
+
+	if c.clusterClients == nil {
+		c.clusterClients = map[string]kube.Interface{}
+	}
+	c.clusterClients[cluster] = clusterClient
+	return clusterClient, nil
+}
+
+// resetClusterClients drops all cached cluster clients, e.g. after the access key changed
+func (c *client) resetClusterClients() {
+	c.clusterClientsMutex.Lock()
+	defer c.clusterClientsMutex.Unlock()
+
+	c.clusterClients = nil
 }
 
 func (c *client) VirtualClusterConfig(cluster, namespace, virtualCluster string) (*rest.Config, error) {
@@ -209,7 +371,12 @@ func (c *client) VirtualCluster(cluster, namespace, virtualCluster string) (kube
 		return nil, err
 	}
 
-	return kube.NewForConfig(restConfig)
+	virtualClusterClient, err := kube.NewForConfig(restConfig)
+	if err != nil {
+		return nil, wrapError(ErrInvalidConfig, err)
+	}
+
+	return virtualClusterClient, nil
 }
 
 func (c *client) Config() *Config {
@@ -229,8 +396,19 @@ func verifyHost(host string) error {
 }
 
 func (c *client) Login(host string, insecure bool, log log.Logger) error {
+	return c.loginWithBrowser(host, fmt.Sprintf(LoginPath, host), insecure, log)
+}
+
+// LoginWithSSO logs into loft via the single sign-on provider configured in loft. It uses the
+// same cli login page as Login, which offers the configured oidc provider and sends the
+// resulting access key to the local callback server.
+func (c *client) LoginWithSSO(host string, insecure bool, log log.Logger) error {
+	log.Info("Please sign in with the single sign-on provider on the loft login page")
+	return c.loginWithBrowser(host, fmt.Sprintf(LoginPath, host), insecure, log)
+}
+
+func (c *client) loginWithBrowser(host, loginUrl string, insecure bool, log log.Logger) error {
 	var (
-		loginUrl   = fmt.Sprintf(LoginPath, host)
 		key        keyStruct
 		keyChannel = make(chan keyStruct)
 	)
@@ -241,7 +419,7 @@ func (c *client) Login(host string, insecure bool, log log.Logger) error {
 	}
 
 	server := startServer(fmt.Sprintf(RedirectPath, host), keyChannel, log)
-	err = open.Run(fmt.Sprintf(LoginPath, host))
+	err = open.Run(loginUrl)
 	if err != nil {
 		return fmt.Errorf("couldn't open the login page in a browser: %v. Please use the --access-key flag for the login command. You can generate an access key here: %s", err, fmt.Sprintf(AccessKeyPath, host))
 	} else {
@@ -286,6 +464,7 @@ func (c *client) LoginWithAccessKey(host, accessKey string, insecure bool) error
 	c.config.AccessKey = accessKey
 	c.config.DirectClusterEndpointToken = ""
 	c.config.DirectClusterEndpointTokenRequested = nil
+	c.resetClusterClients()
 
 	// verify the connection works
 	managementClient, err := c.Management()
@@ -302,7 +481,16 @@ func (c *client) LoginWithAccessKey(host, accessKey string, insecure bool) error
 			}
 		}
 
-		return errors.Errorf("error logging in: %v", err)
+		return ClassifyError(errors.Wrap(err, "error logging in"))
+	}
+
+	// keep the current loft context in sync
+	if c.config.CurrentContext != "" && c.config.Contexts[c.config.CurrentContext] != nil {
+		c.config.Contexts[c.config.CurrentContext] = &Context{
+			Host:      host,
+			Insecure:  insecure,
+			AccessKey: accessKey,
+		}
 	}
 
 	return c.Save()
@@ -310,15 +498,22 @@ func (c *client) LoginWithAccessKey(host, accessKey string, insecure bool) error
 
 func (c *client) restConfig(hostSuffix string) (*rest.Config, error) {
 	if c.config == nil {
-		return nil, errors.New("no config loaded")
+		return nil, wrapError(ErrInvalidConfig, errors.New("no config loaded"))
+	} else if c.envOverrides && c.config.Host != "" && c.config.AccessKey == "" {
+		return nil, wrapError(ErrNotAuthenticated, fmt.Errorf("%s points to another loft than the config, please set %s as well", EnvServer, EnvAccessKey))
 	} else if c.config.Host == "" || c.config.AccessKey == "" {
-		return nil, errors.New("not logged in, please make sure you have run 'loft login [loft-url]'")
+		return nil, wrapError(ErrNotAuthenticated, errors.New("not logged in, please make sure you have run 'loft login [loft-url]'"))
 	}
 
 	// build a rest config
 	config, err := getRestConfig(c.config.Host+hostSuffix, c.config.AccessKey, c.config.Insecure)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(ErrInvalidConfig, err)
+	}
+
+	// retry requests with a refreshed access key if loft rejects the current one
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		return &refreshTransport{client: c, transport: rt, cluster: hostSuffix != "/kubernetes/management"}
 	}
 
 	return config, err
@@ -350,6 +545,7 @@ func getRestConfig(host, token string, insecure bool) (*rest.Config, error) {
 		return nil, err
 	}
 
+	config.UserAgent = upgrade.UserAgent()
 	return config, nil
 }
 
@@ -29,6 +29,33 @@ type Config struct {
 	// last time the direct cluster endpoint token was requested
 	// +optional
 	DirectClusterEndpointTokenRequested *metav1.Time `json:"directClusterEndpointTokenRequested,omitempty"`
+
+	// CurrentContext is the name of the loft context that is currently used
+	// +optional
+	CurrentContext string `json:"currentContext,omitempty"`
+
+	// Contexts holds the named loft instances that can be switched between
+	// +optional
+	Contexts map[string]*Context `json:"contexts,omitempty"`
+
+	// ClusterAliases maps short names to cluster names, the names can be used with --cluster and --target-cluster
+	// +optional
+	ClusterAliases map[string]string `json:"clusterAliases,omitempty"`
+}
+
+// Context defines a named loft instance
+type Context struct {
+	// host is the http endpoint of how to access loft
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// insecure specifies if the loft instance is insecure
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// access key is the access key for the given loft host
+	// +optional
+	AccessKey string `json:"accesskey,omitempty"`
 }
 
 // NewConfig creates a new config
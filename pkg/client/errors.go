@@ -0,0 +1,95 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"net/url"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	// ErrNotAuthenticated is returned if there is no loft login or the access key was rejected
+	ErrNotAuthenticated = errors.New("not authenticated")
+	// ErrForbidden is returned if the user is not allowed to access the requested resource
+	ErrForbidden = errors.New("forbidden")
+	// ErrClusterUnreachable is returned if loft or the requested cluster cannot be reached
+	ErrClusterUnreachable = errors.New("cluster unreachable")
+	// ErrInvalidConfig is returned if the loft config cannot be loaded
+	ErrInvalidConfig = errors.New("invalid config")
+)
+
+// Error wraps an underlying error with one of the error kinds above, so callers
+// can check the kind with errors.Is and still reach the original error
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the given kind
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func wrapError(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Kind: kind, Err: err}
+}
+
+// ClassifyError wraps errors returned by the loft api or a cluster into the matching
+// error kind. Errors that are already classified or cannot be classified are returned as is.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var clientErr *Error
+	if errors.As(err, &clientErr) {
+		return err
+	}
+
+	switch {
+	case kerrors.IsUnauthorized(err):
+		return wrapError(ErrNotAuthenticated, err)
+	case kerrors.IsForbidden(err):
+		return wrapError(ErrForbidden, err)
+	case kerrors.IsServiceUnavailable(err), kerrors.IsTimeout(err), kerrors.IsServerTimeout(err):
+		return wrapError(ErrClusterUnreachable, err)
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return wrapError(ErrClusterUnreachable, err)
+	}
+
+	return err
+}
+
+// ErrorHint returns a hint how to resolve the given error if it is of one of the error kinds above
+func ErrorHint(err error) string {
+	switch {
+	case errors.Is(err, ErrNotAuthenticated):
+		return "Please log in again via 'loft login [loft-url]'"
+	case errors.Is(err, ErrForbidden):
+		return "Please make sure your loft user or team has access to the requested resource"
+	case errors.Is(err, ErrClusterUnreachable):
+		return "Please make sure loft and the cluster are reachable from this machine"
+	case errors.Is(err, ErrInvalidConfig):
+		return "Please check your loft config or log in again via 'loft login [loft-url]'"
+	}
+
+	return ""
+}
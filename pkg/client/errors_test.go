@@ -0,0 +1,80 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"gotest.tools/assert"
+)
+
+type classifyErrorTestCase struct {
+	name string
+	err  error
+
+	expectedKind error
+}
+
+func TestClassifyError(t *testing.T) {
+	spaces := schema.GroupResource{Group: "tenancy.kiosk.sh", Resource: "spaces"}
+	testCases := []classifyErrorTestCase{
+		{
+			name:         "Unauthorized",
+			err:          kerrors.NewUnauthorized("token expired"),
+			expectedKind: ErrNotAuthenticated,
+		},
+		{
+			name:         "Wrapped unauthorized",
+			err:          fmt.Errorf("get self: %w", kerrors.NewUnauthorized("token expired")),
+			expectedKind: ErrNotAuthenticated,
+		},
+		{
+			name:         "Forbidden",
+			err:          kerrors.NewForbidden(spaces, "myspace", fmt.Errorf("access denied")),
+			expectedKind: ErrForbidden,
+		},
+		{
+			name:         "Service unavailable",
+			err:          kerrors.NewServiceUnavailable("agent is not ready"),
+			expectedKind: ErrClusterUnreachable,
+		},
+		{
+			name:         "Connection refused",
+			err:          &url.Error{Op: "Get", URL: "https://loft.example.com", Err: &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}},
+			expectedKind: ErrClusterUnreachable,
+		},
+		{
+			name:         "Already classified",
+			err:          wrapError(ErrInvalidConfig, fmt.Errorf("no config loaded")),
+			expectedKind: ErrInvalidConfig,
+		},
+		{
+			name: "Not found",
+			err:  kerrors.NewNotFound(spaces, "myspace"),
+		},
+		{
+			name: "Other error",
+			err:  fmt.Errorf("something went wrong"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		classified := ClassifyError(testCase.err)
+		assert.Equal(t, testCase.err.Error(), classified.Error(), "Unexpected message in test case %s", testCase.name)
+
+		kind := error(nil)
+		for _, candidate := range []error{ErrNotAuthenticated, ErrForbidden, ErrClusterUnreachable, ErrInvalidConfig} {
+			if errors.Is(classified, candidate) {
+				kind = candidate
+			}
+		}
+		assert.Equal(t, testCase.expectedKind, kind, "Unexpected error kind in test case %s", testCase.name)
+	}
+
+	assert.NilError(t, ClassifyError(nil))
+}
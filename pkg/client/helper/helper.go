@@ -9,11 +9,14 @@ import (
 	"github.com/loft-sh/loftctl/pkg/kubeconfig"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/survey"
+	"github.com/loft-sh/loftctl/pkg/util"
 	"github.com/mgutz/ansi"
 	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"strings"
+	"time"
 )
 
 // ListClusterAccounts lists all the clusters and the corresponding accounts for the current user
@@ -63,6 +66,7 @@ func SelectCluster(baseClient client.Client, log log.Logger) (string, error) {
 	if len(clusterNames) == 0 {
 		return "", fmt.Errorf("the user has no access to any cluster")
 	} else if len(clusterNames) == 1 {
+		log.Infof("Using cluster %s, because it is the only cluster you have access to", ansi.Color(clusterNames[0], "white+b"))
 		return clusterNames[0], nil
 	}
 
@@ -73,6 +77,37 @@ func SelectCluster(baseClient client.Client, log log.Logger) (string, error) {
 	})
 }
 
+// VerifyClusterName checks that the user has access to the given cluster and suggests
+// the closest cluster name if not
+func VerifyClusterName(baseClient client.Client, clusterName string) error {
+	clusters, err := ListClusterAccounts(baseClient)
+	if err != nil {
+		return err
+	}
+
+	return verifyClusterName(clusters, clusterName)
+}
+
+func verifyClusterName(clusters []managementv1.ClusterAccounts, clusterName string) error {
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		if cluster.Cluster.Name == clusterName {
+			return nil
+		}
+
+		clusterNames = append(clusterNames, cluster.Cluster.Name)
+	}
+
+	closest := util.ClosestMatch(clusterName, clusterNames, 4)
+	if closest != "" {
+		return fmt.Errorf("couldn't find cluster %s, did you mean %s?", ansi.Color(clusterName, "white+b"), ansi.Color(closest, "white+b"))
+	} else if len(clusterNames) > 0 {
+		return fmt.Errorf("couldn't find cluster %s, available clusters are: %s", ansi.Color(clusterName, "white+b"), strings.Join(clusterNames, ", "))
+	}
+
+	return fmt.Errorf("couldn't find cluster %s, because you have no access to any cluster", ansi.Color(clusterName, "white+b"))
+}
+
 // SelectAccount lets the user select an account in a cluster
 func SelectAccount(baseClient client.Client, clusterName string, log log.Logger) (string, error) {
 	clusters, err := ListClusterAccounts(baseClient)
@@ -226,38 +261,181 @@ func SelectClusterUserOrTeam(baseClient client.Client, clusterName, userName, te
 	return nil, fmt.Errorf("selected question option not found")
 }
 
-// GetSpaces returns all spaces accessible by the user or team
-func GetSpaces(baseClient client.Client) ([]managementv1.ClusterSpace, error) {
-	kubeClient, err := baseClient.Management()
+// GetSpaces returns all spaces accessible by the user or team. If the user is not allowed to list
+// the spaces through the management api, the spaces are listed in each cluster instead and
+// clusters the user has no access to are skipped with a warning.
+func GetSpaces(baseClient client.Client, log log.Logger) ([]managementv1.ClusterSpace, error) {
+	spaces := []managementv1.ClusterSpace{}
+	err := GetSpacesFunc(baseClient, log, func(clusterSpaces []managementv1.ClusterSpace) error {
+		spaces = append(spaces, clusterSpaces...)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	return spaces, nil
+}
+
+// GetSpacesFunc works like GetSpaces, but calls fn with the spaces as soon as they are retrieved. If the
+// spaces are listed in each cluster, fn is called once per cluster, otherwise once with all spaces.
+func GetSpacesFunc(baseClient client.Client, log log.Logger, fn func(spaces []managementv1.ClusterSpace) error) error {
+	kubeClient, err := baseClient.Management()
+	if err != nil {
+		return err
+	}
+
 	userName, teamName, err := GetCurrentUser(context.TODO(), kubeClient)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	var spaces []managementv1.ClusterSpace
 	if userName != "" {
 		spacesObj, err := kubeClient.Loft().ManagementV1().Users().ListSpaces(context.TODO(), userName, metav1.GetOptions{})
 		if err != nil {
-			return nil, err
+			if kerrors.IsForbidden(err) {
+				return getSpacesPerCluster(baseClient, log, fn)
+			}
+
+			return err
 		}
 
 		spaces = spacesObj.Spaces
 	} else {
 		spacesObj, err := kubeClient.Loft().ManagementV1().Teams().ListSpaces(context.TODO(), teamName, metav1.GetOptions{})
 		if err != nil {
-			return nil, err
+			if kerrors.IsForbidden(err) {
+				return getSpacesPerCluster(baseClient, log, fn)
+			}
+
+			return err
 		}
 
 		spaces = spacesObj.Spaces
 	}
 
+	return fn(spaces)
+}
+
+func getSpacesPerCluster(baseClient client.Client, log log.Logger, fn func(spaces []managementv1.ClusterSpace) error) error {
+	clusters, err := ListClusterAccounts(baseClient)
+	if err != nil {
+		return err
+	}
+
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Cluster.Name)
+	}
+
+	return forEachClusterSpaces(clusterNames, func(clusterName string) ([]managementv1.ClusterSpace, error) {
+		return listClusterSpaces(baseClient, clusterName)
+	}, log, fn)
+}
+
+// collectClusterSpaces lists the spaces of all given clusters and skips the clusters that
+// return a forbidden error, so that the accessible spaces can still be shown
+func collectClusterSpaces(clusterNames []string, listSpaces func(clusterName string) ([]managementv1.ClusterSpace, error), log log.Logger) ([]managementv1.ClusterSpace, error) {
+	spaces := []managementv1.ClusterSpace{}
+	err := forEachClusterSpaces(clusterNames, listSpaces, log, func(clusterSpaces []managementv1.ClusterSpace) error {
+		spaces = append(spaces, clusterSpaces...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return spaces, nil
 }
 
+// forEachClusterSpaces lists the spaces of the given clusters one after another and calls fn with the
+// spaces of each cluster. Clusters that return a forbidden error are skipped.
+func forEachClusterSpaces(clusterNames []string, listSpaces func(clusterName string) ([]managementv1.ClusterSpace, error), log log.Logger, fn func(spaces []managementv1.ClusterSpace) error) error {
+	for _, clusterName := range clusterNames {
+		clusterSpaces, err := listSpaces(clusterName)
+		if err != nil {
+			if kerrors.IsForbidden(err) {
+				log.Warnf("Skipping cluster %s, because you are not allowed to list its spaces", clusterName)
+				continue
+			}
+
+			return errors.Wrapf(err, "list spaces in cluster %s", clusterName)
+		}
+
+		err = fn(clusterSpaces)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func listClusterSpaces(baseClient client.Client, clusterName string) ([]managementv1.ClusterSpace, error) {
+	clusterClient, err := baseClient.Cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	spaceList, err := clusterClient.Kiosk().TenancyV1alpha1().Spaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	spaces := []managementv1.ClusterSpace{}
+	for _, space := range spaceList.Items {
+		clusterSpace := managementv1.ClusterSpace{
+			Space:   space,
+			Cluster: clusterName,
+		}
+
+		// the sleep mode config is optional, so errors are ignored here
+		configs, err := clusterClient.Agent().ClusterV1().SleepModeConfigs(space.Name).List(context.TODO(), metav1.ListOptions{})
+		if err == nil && len(configs.Items) > 0 {
+			clusterSpace.SleepModeConfig = &configs.Items[0]
+		}
+
+		spaces = append(spaces, clusterSpace)
+	}
+
+	return spaces, nil
+}
+
+// WaitForSpacePhase waits until the space reaches the given status phase, e.g. Active
+func WaitForSpacePhase(clusterClient kube.Interface, spaceName, phase string, timeout time.Duration, log log.Logger) error {
+	currentPhase := ""
+	err := util.WaitForCondition(context.TODO(), time.Second, timeout, "space "+spaceName+" to reach phase "+phase, log, func() (bool, error) {
+		space, err := clusterClient.Kiosk().TenancyV1alpha1().Spaces().Get(context.TODO(), spaceName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		currentPhase = string(space.Status.Phase)
+		return strings.EqualFold(currentPhase, phase), nil
+	})
+	if err != nil {
+		if currentPhase != "" {
+			return fmt.Errorf("%v, space is in phase %s", err, currentPhase)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// hasSpace checks if there is a space with exactly the given name in the given cluster or any cluster
+func hasSpace(spaces []managementv1.ClusterSpace, spaceName, clusterName string) bool {
+	for _, space := range spaces {
+		if space.Space.Name == spaceName && (clusterName == "" || space.Cluster == clusterName) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetVirtualClusters returns all virtual clusters the user has access to
 func GetVirtualClusters(baseClient client.Client) ([]managementv1.ClusterVirtualCluster, error) {
 	kubeClient, err := baseClient.Management()
@@ -292,22 +470,55 @@ func GetVirtualClusters(baseClient client.Client) ([]managementv1.ClusterVirtual
 
 // SelectSpaceAndClusterName selects a space and cluster name
 func SelectSpaceAndClusterName(baseClient client.Client, spaceName, clusterName string, log log.Logger) (string, string, error) {
-	spaces, err := GetSpaces(baseClient)
+	return selectSpaceAndClusterName(baseClient, spaceName, clusterName, false, log)
+}
+
+// SelectSpaceAndClusterNameFuzzy selects a space and cluster name like SelectSpaceAndClusterName, but if there
+// is no space with the exact name it uses all spaces that contain the name. Don't use it for destructive commands.
+func SelectSpaceAndClusterNameFuzzy(baseClient client.Client, spaceName, clusterName string, log log.Logger) (string, string, error) {
+	return selectSpaceAndClusterName(baseClient, spaceName, clusterName, true, log)
+}
+
+func selectSpaceAndClusterName(baseClient client.Client, spaceName, clusterName string, fuzzy bool, log log.Logger) (string, string, error) {
+	spaces, err := GetSpaces(baseClient, log)
 	if err != nil {
 		return "", "", err
 	}
 
+	clusters, err := ListClusterAccounts(baseClient)
+	if err != nil {
+		return "", "", err
+	}
+
+	// if the user has only access to a single cluster there is no need to ask for it
+	singleCluster := false
+	if clusterName != "" {
+		err = verifyClusterName(clusters, clusterName)
+		if err != nil {
+			return "", "", err
+		}
+	} else if len(clusters) == 1 {
+		clusterName = clusters[0].Cluster.Name
+		singleCluster = true
+		log.Infof("Using cluster %s, because it is the only cluster you have access to", ansi.Color(clusterName, "white+b"))
+	}
+
 	currentContext, err := kubeconfig.CurrentContext()
 	if err != nil {
 		return "", "", errors.Wrap(err, "loading kubernetes config")
 	}
 
+	// if there is no space with the exact name, we use all spaces that contain the name
+	partialMatch := fuzzy && spaceName != "" && hasSpace(spaces, spaceName, clusterName) == false
+
 	isLoftContext, cluster, namespace, vCluster := kubeconfig.ParseContext(currentContext)
 	matchedSpaces := []managementv1.ClusterSpace{}
 	questionOptionsUnformatted := [][]string{}
 	defaultIndex := 0
 	for _, space := range spaces {
-		if spaceName != "" && space.Space.Name != spaceName {
+		if spaceName != "" && partialMatch == false && space.Space.Name != spaceName {
+			continue
+		} else if partialMatch && strings.Contains(space.Space.Name, spaceName) == false {
 			continue
 		} else if clusterName != "" && space.Cluster != clusterName {
 			continue
@@ -318,19 +529,32 @@ func SelectSpaceAndClusterName(baseClient client.Client, spaceName, clusterName
 		}
 
 		matchedSpaces = append(matchedSpaces, space)
-		questionOptionsUnformatted = append(questionOptionsUnformatted, []string{space.Space.Name, space.Cluster})
+		if singleCluster {
+			questionOptionsUnformatted = append(questionOptionsUnformatted, []string{space.Space.Name})
+		} else {
+			questionOptionsUnformatted = append(questionOptionsUnformatted, []string{space.Space.Name, space.Cluster})
+		}
+	}
+
+	questionFormat := "Space: %s | Cluster: %s"
+	if singleCluster {
+		questionFormat = "Space: %s"
 	}
 
-	questionOptions := formatOptions("Space: %s | Cluster: %s", questionOptionsUnformatted)
+	questionOptions := formatOptions(questionFormat, questionOptionsUnformatted)
 	if len(questionOptions) == 0 {
 		if spaceName == "" {
 			return "", "", fmt.Errorf("couldn't find any space")
-		} else if clusterName != "" {
+		} else if clusterName != "" && singleCluster == false {
 			return "", "", fmt.Errorf("couldn't find space %s in cluster %s", ansi.Color(spaceName, "white+b"), ansi.Color(clusterName, "white+b"))
 		}
 
 		return "", "", fmt.Errorf("couldn't find space %s", ansi.Color(spaceName, "white+b"))
 	} else if len(questionOptions) == 1 {
+		if partialMatch {
+			log.Infof("Using space %s, because it is the only space matching %s", ansi.Color(matchedSpaces[0].Space.Name, "white+b"), spaceName)
+		}
+
 		return matchedSpaces[0].Space.Name, matchedSpaces[0].Cluster, nil
 	}
 
@@ -464,3 +688,17 @@ func formatOptions(format string, options [][]string) []string {
 
 	return retOptions
 }
+
+// SpaceIdleTime returns how long the space has been sleeping or, if the space is not sleeping,
+// how long ago the last activity was. Spaces without any activity information are never idle.
+func SpaceIdleTime(space managementv1.ClusterSpace) time.Duration {
+	if space.SleepModeConfig == nil {
+		return 0
+	} else if space.SleepModeConfig.Status.SleepingSince != 0 {
+		return time.Now().Sub(time.Unix(space.SleepModeConfig.Status.SleepingSince, 0))
+	} else if space.SleepModeConfig.Status.LastActivity != 0 {
+		return time.Now().Sub(time.Unix(space.SleepModeConfig.Status.LastActivity, 0))
+	}
+
+	return 0
+}
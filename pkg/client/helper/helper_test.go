@@ -0,0 +1,60 @@
+package helper
+
+import (
+	"fmt"
+	"testing"
+
+	managementv1 "github.com/loft-sh/api/pkg/apis/management/v1"
+	"github.com/loft-sh/loftctl/pkg/log"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"gotest.tools/assert"
+)
+
+func TestCollectClusterSpacesSkipsForbiddenCluster(t *testing.T) {
+	listSpaces := func(clusterName string) ([]managementv1.ClusterSpace, error) {
+		if clusterName == "forbidden" {
+			return nil, kerrors.NewForbidden(schema.GroupResource{Group: "tenancy.kiosk.sh", Resource: "spaces"}, "", fmt.Errorf("access denied"))
+		}
+
+		return []managementv1.ClusterSpace{{Cluster: clusterName}}, nil
+	}
+
+	spaces, err := collectClusterSpaces([]string{"first", "forbidden", "second"}, listSpaces, log.Discard)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(spaces), "Unexpected number of spaces")
+	assert.Equal(t, "first", spaces[0].Cluster, "Unexpected cluster of first space")
+	assert.Equal(t, "second", spaces[1].Cluster, "Unexpected cluster of second space")
+}
+
+func TestCollectClusterSpacesFailsOnOtherErrors(t *testing.T) {
+	listSpaces := func(clusterName string) ([]managementv1.ClusterSpace, error) {
+		if clusterName == "broken" {
+			return nil, fmt.Errorf("connection refused")
+		}
+
+		return []managementv1.ClusterSpace{{Cluster: clusterName}}, nil
+	}
+
+	_, err := collectClusterSpaces([]string{"first", "broken"}, listSpaces, log.Discard)
+	assert.ErrorContains(t, err, "list spaces in cluster broken")
+}
+
+func TestForEachClusterSpacesCallsPerCluster(t *testing.T) {
+	listSpaces := func(clusterName string) ([]managementv1.ClusterSpace, error) {
+		return []managementv1.ClusterSpace{{Cluster: clusterName}}, nil
+	}
+
+	calls := []string{}
+	err := forEachClusterSpaces([]string{"first", "second", "third"}, listSpaces, log.Discard, func(spaces []managementv1.ClusterSpace) error {
+		calls = append(calls, spaces[0].Cluster)
+		if spaces[0].Cluster == "second" {
+			return fmt.Errorf("write failed")
+		}
+
+		return nil
+	})
+	assert.ErrorContains(t, err, "write failed")
+	assert.DeepEqual(t, []string{"first", "second"}, calls)
+}
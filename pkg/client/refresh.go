@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// refreshTransport sends every request with the current access key of the client. If loft
+// rejects the access key, it adopts the access key of the config file, which another loft login
+// might have replaced in the meantime, and retries the request once. Access keys cannot be
+// renewed without a new login, so a request is not retried if the config holds no other key.
+type refreshTransport struct {
+	client    *client
+	transport http.RoundTripper
+
+	// cluster is true for requests to a cluster or virtual cluster
+	cluster bool
+}
+
+func (t *refreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	accessKey := t.client.currentAccessKey()
+	resp, err := t.transport.RoundTrip(withAccessKey(req, accessKey))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// we cannot retry requests whose body was already consumed
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	refreshedAccessKey, err := t.client.refreshAccessKey(accessKey)
+	if err != nil {
+		return resp, nil
+	}
+
+	// the cached direct cluster endpoint token was issued for the rejected access key,
+	// so request a new one for the refreshed access key
+	if t.cluster {
+		_, err = t.client.DirectClusterEndpointToken(true)
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	retry := withAccessKey(req, refreshedAccessKey)
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	resp.Body.Close()
+	return t.transport.RoundTrip(retry)
+}
+
+func withAccessKey(req *http.Request, accessKey string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+accessKey)
+	return clone
+}
+
+func (c *client) currentAccessKey() string {
+	c.accessKeyMutex.Lock()
+	defer c.accessKeyMutex.Unlock()
+
+	return c.config.AccessKey
+}
+
+// refreshAccessKey reloads the config file after loft rejected the given access key and adopts
+// it, if it holds another access key for the same loft. The config file is not written, as it
+// already contains the refreshed access key.
+func (c *client) refreshAccessKey(rejectedAccessKey string) (string, error) {
+	c.accessKeyMutex.Lock()
+	defer c.accessKeyMutex.Unlock()
+
+	// another request might have refreshed the access key already
+	if c.config.AccessKey != rejectedAccessKey {
+		return c.config.AccessKey, nil
+	}
+
+	config, err := loadConfig(c.configPath)
+	if err != nil {
+		return "", wrapError(ErrInvalidConfig, err)
+	}
+
+	reloaded := *config
+	applyEnvironmentOverrides(&reloaded)
+	if reloaded.Host != c.config.Host || reloaded.AccessKey == "" || reloaded.AccessKey == rejectedAccessKey {
+		return "", wrapError(ErrNotAuthenticated, fmt.Errorf("the access key was rejected by loft, please run 'loft login %s' again", c.config.Host))
+	}
+
+	c.setConfig(config)
+	return c.config.AccessKey, nil
+}
@@ -1,19 +1,29 @@
 package clihelper
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	storagev1 "github.com/loft-sh/api/pkg/apis/storage/v1"
 	loftclientset "github.com/loft-sh/api/pkg/client/clientset_generated/clientset"
 	"github.com/loft-sh/apimachinery/pkg/portforward"
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/loft-sh/loftctl/pkg/survey"
+	"github.com/loft-sh/loftctl/pkg/upgrade"
+	"github.com/loft-sh/loftctl/pkg/util"
 	"github.com/pkg/errors"
+	"io"
 	"io/ioutil"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -21,17 +31,45 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/transport/spdy"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
+// SplitHostPath splits a loft host in the form hostname[/path] into the hostname and the path
+func SplitHostPath(host string) (string, string) {
+	splitted := strings.SplitN(host, "/", 2)
+	if len(splitted) == 1 {
+		return splitted[0], ""
+	}
+
+	return splitted[0], strings.TrimSuffix("/"+splitted[1], "/")
+}
+
+// IngressHostValues returns the helm values to expose loft at the given host, which might contain a sub path
+func IngressHostValues(host string) []string {
+	hostname, path := SplitHostPath(host)
+	values := []string{
+		"--set",
+		"ingress.host=" + hostname,
+	}
+	if path != "" {
+		values = append(values, "--set", "ingress.path="+path, "--set", "ingress.pathType=Prefix")
+	}
+
+	return values
+}
+
+// GetLoftIngressHost returns the host loft is exposed at, including the sub path if there is one
 func GetLoftIngressHost(kubeClient kubernetes.Interface, namespace string) (string, error) {
 	ingress, err := kubeClient.NetworkingV1().Ingresses(namespace).Get(context.TODO(), "loft-ingress", metav1.GetOptions{})
 	if err != nil {
@@ -41,12 +79,20 @@ func GetLoftIngressHost(kubeClient kubernetes.Interface, namespace string) (stri
 		} else {
 			// find host
 			for _, rule := range ingress.Spec.Rules {
+				if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 {
+					return rule.Host + strings.TrimSuffix(rule.HTTP.Paths[0].Path, "/"), nil
+				}
+
 				return rule.Host, nil
 			}
 		}
 	} else {
 		// find host
 		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 {
+				return rule.Host + strings.TrimSuffix(rule.HTTP.Paths[0].Path, "/"), nil
+			}
+
 			return rule.Host, nil
 		}
 	}
@@ -54,6 +100,65 @@ func GetLoftIngressHost(kubeClient kubernetes.Interface, namespace string) (stri
 	return "", fmt.Errorf("couldn't find any host in loft ingress '%s/loft-ingress', please make sure you have not changed any deployed resources")
 }
 
+// podState is the readiness state of a pod
+type podState struct {
+	// Ready is true if all init containers have completed and all containers are running and ready
+	Ready bool
+	// Failed is the status of a container that has terminated or is crash looping
+	Failed *corev1.ContainerStatus
+	// Waiting is the status of a container that is still waiting to start
+	Waiting *corev1.ContainerStatus
+}
+
+// getPodState determines the readiness of the given pod. A pod is only ready if all init containers
+// have completed successfully and all containers, including sidecars, are running and ready. The
+// main container must be among them.
+func getPodState(pod *corev1.Pod, mainContainer string) podState {
+	state := podState{}
+	for i := range pod.Status.InitContainerStatuses {
+		initContainerStatus := &pod.Status.InitContainerStatuses[i]
+		if initContainerStatus.State.Terminated != nil && initContainerStatus.State.Terminated.ExitCode == 0 {
+			continue
+		} else if isContainerFailed(initContainerStatus) {
+			state.Failed = initContainerStatus
+		} else if initContainerStatus.State.Waiting != nil {
+			state.Waiting = initContainerStatus
+		}
+
+		return state
+	}
+	if len(pod.Status.InitContainerStatuses) < len(pod.Spec.InitContainers) {
+		return state
+	}
+
+	allReady := true
+	found := false
+	for i := range pod.Status.ContainerStatuses {
+		containerStatus := &pod.Status.ContainerStatuses[i]
+		if containerStatus.State.Running != nil && containerStatus.Ready {
+			if containerStatus.Name == mainContainer {
+				found = true
+			}
+
+			continue
+		} else if isContainerFailed(containerStatus) {
+			state.Failed = containerStatus
+			return state
+		} else if containerStatus.State.Waiting != nil && state.Waiting == nil {
+			state.Waiting = containerStatus
+		}
+
+		allReady = false
+	}
+
+	state.Ready = allReady && found && len(pod.Status.ContainerStatuses) >= len(pod.Spec.Containers)
+	return state
+}
+
+func isContainerFailed(containerStatus *corev1.ContainerStatus) bool {
+	return containerStatus.State.Terminated != nil || (containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == "CrashLoopBackOff")
+}
+
 func WaitForReadyLoftAgentPod(kubeClient kubernetes.Interface, namespace string, log log.Logger) error {
 	// wait until we have a running loft pod
 	err := wait.Poll(time.Second*2, time.Minute*10, func() (bool, error) {
@@ -72,21 +177,7 @@ func WaitForReadyLoftAgentPod(kubeClient kubernetes.Interface, namespace string,
 			return pods.Items[i].CreationTimestamp.After(pods.Items[j].CreationTimestamp.Time)
 		})
 
-		loftPod := &pods.Items[0]
-		found := false
-		for _, containerStatus := range loftPod.Status.ContainerStatuses {
-			if containerStatus.State.Running != nil && containerStatus.Ready {
-				if containerStatus.Name == "agent" {
-					found = true
-				}
-
-				continue
-			}
-
-			return false, nil
-		}
-
-		return found, nil
+		return getPodState(&pods.Items[0], "agent").Ready, nil
 	})
 	if err != nil {
 		return err
@@ -116,36 +207,26 @@ func WaitForReadyLoftPod(kubeClient kubernetes.Interface, namespace string, log
 		})
 
 		loftPod := &pods.Items[0]
-		found := false
-		for _, containerStatus := range loftPod.Status.ContainerStatuses {
-			if containerStatus.State.Running != nil && containerStatus.Ready {
-				if containerStatus.Name == "manager" {
-					found = true
-				}
-
-				continue
-			} else if containerStatus.State.Terminated != nil || (containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == "CrashLoopBackOff") {
-				out, err := kubeClient.CoreV1().Pods(namespace).GetLogs(loftPod.Name, &corev1.PodLogOptions{
-					Container: "manager",
-				}).Do(context.Background()).Raw()
-				if err != nil {
-					return false, fmt.Errorf("There seems to be an issue with loft starting up. Please reach out to our support at https://loft.sh/")
-				}
-				if strings.Contains(string(out), "register instance: Post \"https://license.loft.sh/register\": dial tcp") {
-					return false, fmt.Errorf("Loft logs: \n%v \nThere seems to be an issue with loft starting up. Looks like you try to install Loft into an air-gapped environment, please reach out to our support at https://loft.sh/ for an offline license and take a look at the air-gapped installation guide https://loft.sh/docs/guides/administration/air-gapped-installation", string(out))
-				}
-
-				return false, fmt.Errorf("Loft logs: \n%v \nThere seems to be an issue with loft starting up. Please reach out to our support at https://loft.sh/", string(out))
-			} else if containerStatus.State.Waiting != nil && time.Now().After(now.Add(time.Minute*3)) && warningPrinted == false {
-				log.Warnf("There might be an issue with loft starting up. The container is still waiting, because of %s (%s). Please reach out to our support at https://loft.sh/", containerStatus.State.Waiting.Message, containerStatus.State.Waiting.Reason)
-				warningPrinted = true
+		state := getPodState(loftPod, "manager")
+		if state.Failed != nil {
+			out, err := kubeClient.CoreV1().Pods(namespace).GetLogs(loftPod.Name, &corev1.PodLogOptions{
+				Container: state.Failed.Name,
+			}).Do(context.Background()).Raw()
+			if err != nil {
+				return false, fmt.Errorf("There seems to be an issue with loft starting up. Please reach out to our support at https://loft.sh/")
+			}
+			if strings.Contains(string(out), "register instance: Post \"https://license.loft.sh/register\": dial tcp") {
+				return false, fmt.Errorf("Loft logs: \n%v \nThere seems to be an issue with loft starting up. Looks like you try to install Loft into an air-gapped environment, please reach out to our support at https://loft.sh/ for an offline license and take a look at the air-gapped installation guide https://loft.sh/docs/guides/administration/air-gapped-installation", string(out))
 			}
 
-			return false, nil
+			return false, fmt.Errorf("Loft logs (container %s): \n%v \nThere seems to be an issue with loft starting up. Please reach out to our support at https://loft.sh/", state.Failed.Name, string(out))
+		} else if state.Waiting != nil && time.Now().After(now.Add(time.Minute*3)) && warningPrinted == false {
+			log.Warnf("There might be an issue with loft starting up. The container %s is still waiting, because of %s (%s). Please reach out to our support at https://loft.sh/", state.Waiting.Name, state.Waiting.State.Waiting.Message, state.Waiting.State.Waiting.Reason)
+			warningPrinted = true
 		}
 
 		pod = loftPod
-		return found, nil
+		return state.Ready, nil
 	})
 	if err != nil {
 		return nil, err
@@ -154,7 +235,59 @@ func WaitForReadyLoftPod(kubeClient kubernetes.Interface, namespace string, log
 	return pod, nil
 }
 
-func StartPortForwarding(config *rest.Config, client kubernetes.Interface, pod *corev1.Pod, localPort string, log log.Logger) (chan struct{}, error) {
+// LoftAPIServiceName is the name of the apiservice that serves the loft management api
+const LoftAPIServiceName = "v1.management.loft.sh"
+
+// WaitForLoftAPIService waits until the loft management apiservice reports the Available condition
+func WaitForLoftAPIService(restConfig *rest.Config, log log.Logger) error {
+	apiRegistrationClient, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	lastMessage := ""
+	err = wait.Poll(time.Second*2, time.Minute*5, func() (bool, error) {
+		apiService, err := apiRegistrationClient.ApiregistrationV1().APIServices().Get(context.TODO(), LoftAPIServiceName, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		for _, condition := range apiService.Status.Conditions {
+			if condition.Type != apiregistrationv1.Available {
+				continue
+			} else if condition.Status == apiregistrationv1.ConditionTrue {
+				return true, nil
+			}
+
+			lastMessage = condition.Message
+		}
+
+		if time.Now().After(now.Add(time.Minute)) && lastMessage != "" {
+			log.Warnf("APIService %s is not available yet: %s", LoftAPIServiceName, lastMessage)
+			now = time.Now()
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		if lastMessage != "" {
+			return fmt.Errorf("apiservice %s did not become available: %s", LoftAPIServiceName, lastMessage)
+		}
+
+		return fmt.Errorf("apiservice %s did not become available: %v", LoftAPIServiceName, err)
+	}
+
+	return nil
+}
+
+// StartPortForwarding forwards the given local port to the loft pod. If the local port is 0, a free port
+// is chosen. It returns the local port that is actually used.
+func StartPortForwarding(config *rest.Config, client kubernetes.Interface, pod *corev1.Pod, localPort string, log log.Logger) (chan struct{}, string, error) {
 	log.Info("Starting port-forwarding to the loft pod")
 	execRequest := client.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -164,7 +297,7 @@ func StartPortForwarding(config *rest.Config, client kubernetes.Interface, pod *
 
 	t, upgrader, err := spdy.RoundTripperFor(config)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: t}, "POST", execRequest.URL())
@@ -173,7 +306,7 @@ func StartPortForwarding(config *rest.Config, client kubernetes.Interface, pod *
 	stopChan := make(chan struct{})
 	forwarder, err := portforward.New(dialer, []string{localPort + ":" + strconv.Itoa(443)}, stopChan, readyChan, errChan, ioutil.Discard, ioutil.Discard)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	go func() {
@@ -186,10 +319,18 @@ func StartPortForwarding(config *rest.Config, client kubernetes.Interface, pod *
 	// wait till ready
 	select {
 	case err = <-errChan:
-		return nil, err
+		return nil, "", err
 	case <-readyChan:
 	case <-stopChan:
-		return nil, fmt.Errorf("stopped before ready")
+		return nil, "", fmt.Errorf("stopped before ready")
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopChan)
+		return nil, "", err
+	} else if len(ports) > 0 {
+		localPort = strconv.Itoa(int(ports[0].Local))
 	}
 
 	// start watcher
@@ -206,7 +347,7 @@ func StartPortForwarding(config *rest.Config, client kubernetes.Interface, pod *
 		}
 	}()
 
-	return stopChan, nil
+	return stopChan, localPort, nil
 }
 
 func GetLoftDefaultPassword(kubeClient kubernetes.Interface, namespace string) (string, error) {
@@ -219,10 +360,16 @@ func GetLoftDefaultPassword(kubeClient kubernetes.Interface, namespace string) (
 				},
 			}, metav1.CreateOptions{})
 			if err != nil {
+				if kerrors.IsForbidden(err) {
+					return "", fmt.Errorf("you are not allowed to create the namespace %s: %v. Please make sure you are allowed to create namespaces or create the namespace %s before running this command", namespace, err, namespace)
+				}
+
 				return "", err
 			}
 
 			return string(loftNamespace.UID), nil
+		} else if kerrors.IsForbidden(err) {
+			return "", fmt.Errorf("you are not allowed to access the namespace %s: %v. Please make sure you are allowed to get and create namespaces", namespace, err)
 		}
 
 		return "", err
@@ -236,34 +383,203 @@ type version struct {
 }
 
 func IsLoftReachable(host string) (bool, error) {
-	// wait until loft is reachable at the given url
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
+	loftVersion, err := GetLoftVersion(host)
+	if err != nil {
+		return false, err
+	}
+
+	return loftVersion != "", nil
+}
+
+// LoftReachability classifies why loft is or is not reachable at a host
+type LoftReachability string
+
+const (
+	// LoftReachable means loft answered the version request successfully
+	LoftReachable LoftReachability = "OK"
+	// LoftDNSFailure means the host could not be resolved
+	LoftDNSFailure LoftReachability = "DNSFailure"
+	// LoftConnectionRefused means nothing is listening at the resolved address
+	LoftConnectionRefused LoftReachability = "ConnectionRefused"
+	// LoftTLSFailure means the tls handshake failed
+	LoftTLSFailure LoftReachability = "TLSFailure"
+	// LoftHTTPError means the host answered with an unexpected status code, e.g. 503 while loft is starting
+	LoftHTTPError LoftReachability = "HTTPError"
+	// LoftUnreachable means the request failed for another reason, e.g. a timeout
+	LoftUnreachable LoftReachability = "Unreachable"
+)
+
+// LoftStatus is the result of probing the loft version endpoint
+type LoftStatus struct {
+	Reachability LoftReachability
+	StatusCode   int
+	Err          error
+
+	// Version is the version loft reported if it is reachable
+	Version string
+}
+
+// String returns a human readable description of the status
+func (s *LoftStatus) String() string {
+	switch s.Reachability {
+	case LoftReachable:
+		return "loft is reachable"
+	case LoftDNSFailure:
+		return "the host does not resolve, please check your DNS configuration"
+	case LoftConnectionRefused:
+		return "the connection was refused, please check that the ingress controller is exposed at this address"
+	case LoftTLSFailure:
+		return fmt.Sprintf("the tls handshake failed (%v)", s.Err)
+	case LoftHTTPError:
+		return fmt.Sprintf("the host responded with status code %d, loft might still be starting", s.StatusCode)
+	}
+
+	return fmt.Sprintf("the request failed (%v)", s.Err)
+}
+
+// CheckLoftReachability probes the version endpoint of loft at the given host and classifies
+// the result, so that network problems can be told apart from a loft that is not ready yet
+func CheckLoftReachability(host string) *LoftStatus {
+	resp, err := NewLoftProbeClient().Get("https://" + host + "/version")
+	if err != nil {
+		return &LoftStatus{Reachability: classifyRequestError(err), Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &LoftStatus{Reachability: LoftHTTPError, StatusCode: resp.StatusCode}
+	}
+
+	loftVersion, err := readLoftVersion("https://"+host+"/version", resp.Body)
+	if err != nil {
+		return &LoftStatus{Reachability: LoftUnreachable, StatusCode: resp.StatusCode, Err: err}
+	}
+
+	return &LoftStatus{Reachability: LoftReachable, StatusCode: resp.StatusCode, Version: loftVersion}
+}
+
+// readLoftVersion decodes the response body of the loft version endpoint at the given url
+func readLoftVersion(url string, body io.Reader) (string, error) {
+	out, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", errors.Wrapf(err, "read response from %s", url)
+	}
+
+	v := &version{}
+	err = json.Unmarshal(out, v)
+	if err != nil {
+		return "", fmt.Errorf("error decoding response from %s: %v. Try running 'loft start --reset'", url, err)
+	} else if v.Version == "" {
+		return "", fmt.Errorf("unexpected response from %s: %s. Try running 'loft start --reset'", url, string(out))
+	}
+
+	return v.Version, nil
+}
+
+func classifyRequestError(err error) LoftReachability {
+	var dnsErr *net.DNSError
+	var recordHeaderErr tls.RecordHeaderError
+	if errors.As(err, &dnsErr) {
+		return LoftDNSFailure
+	} else if errors.Is(err, syscall.ECONNREFUSED) {
+		return LoftConnectionRefused
+	} else if errors.As(err, &recordHeaderErr) || strings.Contains(err.Error(), "tls:") {
+		return LoftTLSFailure
+	}
+
+	return LoftUnreachable
+}
+
+// LoftRequestTimeout is the timeout for a single request that probes if loft is reachable, so
+// that a hanging connection doesn't block the surrounding poll loop
+const LoftRequestTimeout = time.Second * 5
+
+// NewLoftProbeClient returns the http client used to probe if loft is reachable
+func NewLoftProbeClient() *http.Client {
+	return &http.Client{
+		Timeout: LoftRequestTimeout,
+		Transport: &userAgentTransport{
+			transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+					MinVersion:         tls.VersionTLS12,
+				},
 			},
 		},
 	}
+}
+
+type userAgentTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", upgrade.UserAgent())
+	return t.transport.RoundTrip(req)
+}
+
+// GetLoftVersion returns the version of the loft instance reachable at the given host. If loft
+// is not reachable, an empty version is returned
+func GetLoftVersion(host string) (string, error) {
+	// wait until loft is reachable at the given url
+	client := NewLoftProbeClient()
 	url := "https://" + host + "/version"
 	resp, err := client.Get(url)
-	if err == nil && resp.StatusCode == http.StatusOK {
-		out, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return false, nil
-		}
+	if err != nil {
+		return "", nil
+	}
+	defer resp.Body.Close()
 
-		v := &version{}
-		err = json.Unmarshal(out, v)
-		if err != nil {
-			return false, fmt.Errorf("error decoding response from %s: %v. Try running 'loft start --reset'", url, err)
-		} else if v.Version == "" {
-			return false, fmt.Errorf("unexpected response from %s: %s. Try running 'loft start --reset'", url, string(out))
-		}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil
+	}
 
-		return true, nil
+	return readLoftVersion(url, resp.Body)
+}
+
+// CertificateExpiryWarningPeriod is the period before the certificate expiry in which we start to warn the user
+const CertificateExpiryWarningPeriod = time.Hour * 24 * 14
+
+// GetLoftCertificate returns the leaf certificate that is presented by the loft instance at the given host
+func GetLoftCertificate(host string) (*x509.Certificate, error) {
+	hostname, _ := SplitHostPath(host)
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second * 5}, "tcp", net.JoinHostPort(hostname, "443"), &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS12,
+	})
+	if err != nil {
+		return nil, err
 	}
+	defer conn.Close()
 
-	return false, nil
+	certificates := conn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificate presented by %s", host)
+	}
+
+	return certificates[0], nil
+}
+
+// PrintLoftCertificateWarnings inspects the certificate presented by the loft instance at the
+// given host and warns if it is self-signed or expires soon
+func PrintLoftCertificateWarnings(host string, log log.Logger) {
+	certificate, err := GetLoftCertificate(host)
+	if err != nil {
+		log.Warnf("Couldn't inspect the certificate of https://%s: %v", host, err)
+		return
+	}
+
+	expiresIn := certificate.NotAfter.Sub(time.Now())
+	if expiresIn <= 0 {
+		log.Warnf("The certificate of https://%s has expired on %s", host, certificate.NotAfter.Format(time.RFC1123))
+	} else if expiresIn < CertificateExpiryWarningPeriod {
+		log.Warnf("The certificate of https://%s expires on %s, please make sure it is renewed in time", host, certificate.NotAfter.Format(time.RFC1123))
+	}
+
+	if bytes.Equal(certificate.RawIssuer, certificate.RawSubject) {
+		log.Warnf("The certificate of https://%s is self-signed (issuer: %s), follow this guide to add a valid certificate: https://loft.sh/docs/administration/ssl", host, certificate.Issuer.String())
+	}
 }
 
 func IsLocalCluster(host string, log log.Logger) bool {
@@ -337,16 +653,88 @@ func AskForHost(log log.Logger) (string, error) {
 }
 
 func EnterHostNameQuestion(log log.Logger) (string, error) {
-	return log.Question(&survey.QuestionOptions{
-		Question: "Enter a hostname for your loft instance (e.g. loft.my-domain.tld): \n",
+	answer, err := log.Question(&survey.QuestionOptions{
+		Question: "Enter a hostname for your loft instance, optionally with a sub path (e.g. loft.my-domain.tld or my-domain.tld/loft): \n",
 		ValidationFunc: func(answer string) error {
 			u, err := url.Parse("https://" + answer)
-			if err != nil || u.Path != "" || u.Port() != "" || len(strings.Split(answer, ".")) < 2 {
-				return fmt.Errorf("please enter a valid hostname without protocol (https://), without path and without port, e.g. loft.my-domain.tld")
+			if err != nil || u.Port() != "" || u.RawQuery != "" || u.Fragment != "" || len(strings.Split(u.Hostname(), ".")) < 2 {
+				return fmt.Errorf("please enter a valid hostname without protocol (https://) and without port, e.g. loft.my-domain.tld or my-domain.tld/loft")
 			}
 			return nil
 		},
 	})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(answer, "/"), nil
+}
+
+// WaitForIngressControllerAddress waits until the ingress-nginx controller service got an external ip
+// or hostname from the load balancer and returns it
+func WaitForIngressControllerAddress(kubeClient kubernetes.Interface, timeout time.Duration, log log.Logger) (string, error) {
+	address := ""
+	err := util.WaitForCondition(context.TODO(), time.Second*5, timeout, "the ingress-nginx load balancer to get an external address", log, func() (bool, error) {
+		service, err := kubeClient.CoreV1().Services("ingress-nginx").Get(context.TODO(), "ingress-nginx-controller", metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				address = ingress.IP
+				return true, nil
+			} else if ingress.Hostname != "" {
+				address = ingress.Hostname
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return address, nil
+}
+
+// HasDefaultStorageClass checks if there is a storage class in the cluster that is marked as default
+func HasDefaultStorageClass(kubeClient kubernetes.Interface) (bool, error) {
+	storageClasses, err := kubeClient.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" || storageClass.Annotations["storageclass.beta.kubernetes.io/is-default-class"] == "true" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// FindLoftInstallations returns the namespaces of all loft deployments in the cluster
+func FindLoftInstallations(kubeClient kubernetes.Interface) ([]string, error) {
+	deployments, err := kubeClient.AppsV1().Deployments(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: "app=loft"})
+	if err != nil {
+		return nil, fmt.Errorf("error accessing kubernetes cluster: %v", err)
+	}
+
+	namespaces := []string{}
+	for _, deployment := range deployments.Items {
+		if deployment.Name == "loft" {
+			namespaces = append(namespaces, deployment.Namespace)
+		}
+	}
+
+	sort.Strings(namespaces)
+	return namespaces, nil
 }
 
 func IsLoftAlreadyInstalled(kubeClient kubernetes.Interface, namespace string) (bool, error) {
@@ -362,18 +750,47 @@ func IsLoftAlreadyInstalled(kubeClient kubernetes.Interface, namespace string) (
 	return true, nil
 }
 
-func UninstallLoft(kubeClient kubernetes.Interface, restConfig *rest.Config, kubeContext, namespace string, log log.Logger) error {
-	log.StartWait("Uninstalling loft...")
-	defer log.StopWait()
+// GetHelmReleaseStatus returns the status of the given helm release, e.g. deployed, failed or pending-install.
+// If the release does not exist an empty string is returned.
+func GetHelmReleaseStatus(kubeContext, namespace, releaseName string) (string, error) {
+	args := []string{
+		"status",
+		releaseName,
+		"--kube-context",
+		kubeContext,
+		"--namespace",
+		namespace,
+		"--output",
+		"json",
+	}
+	output, err := exec.Command("helm", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if strings.Contains(string(exitErr.Stderr), "not found") {
+				return "", nil
+			}
 
-	deploy, err := kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), "loft", metav1.GetOptions{})
+			return "", fmt.Errorf("error during helm command: %s (%v)", string(exitErr.Stderr), err)
+		}
+
+		return "", fmt.Errorf("error during helm command: %v", err)
+	}
+
+	release := struct {
+		Info struct {
+			Status string `json:"status"`
+		} `json:"info"`
+	}{}
+	err = json.Unmarshal(output, &release)
 	if err != nil {
-		return err
-	} else if deploy.Labels == nil || deploy.Labels["release"] == "" {
-		return fmt.Errorf("loft was not installed via helm, cannot delete it then")
+		return "", errors.Wrap(err, "parse helm status")
 	}
 
-	releaseName := deploy.Labels["release"]
+	return release.Info.Status, nil
+}
+
+// UninstallHelmRelease uninstalls the given helm release
+func UninstallHelmRelease(kubeContext, namespace, releaseName string, log log.Logger) error {
 	args := []string{
 		"uninstall",
 		releaseName,
@@ -388,6 +805,28 @@ func UninstallLoft(kubeClient kubernetes.Interface, restConfig *rest.Config, kub
 	if err != nil {
 		return fmt.Errorf("error during helm command: %s (%v)", string(output), err)
 	}
+	printHelmOutput(output, log)
+
+	return nil
+}
+
+// UninstallLoft removes the loft helm release, the validating webhook and apiservice of loft and the admin user.
+// It waits up to the given timeout until the webhook and apiservice are deleted, so loft can be reinstalled right away.
+func UninstallLoft(kubeClient kubernetes.Interface, restConfig *rest.Config, kubeContext, namespace string, timeout time.Duration, log log.Logger) error {
+	log.StartWait("Uninstalling loft...")
+	defer log.StopWait()
+
+	deploy, err := kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), "loft", metav1.GetOptions{})
+	if err != nil {
+		return err
+	} else if deploy.Labels == nil || deploy.Labels["release"] == "" {
+		return fmt.Errorf("loft was not installed via helm, cannot delete it then")
+	}
+
+	err = UninstallHelmRelease(kubeContext, namespace, deploy.Labels["release"], log)
+	if err != nil {
+		return err
+	}
 
 	// wait for the loft pods to terminate
 	err = wait.Poll(time.Second, time.Minute*10, func() (bool, error) {
@@ -413,11 +852,33 @@ func UninstallLoft(kubeClient kubernetes.Interface, restConfig *rest.Config, kub
 		return err
 	}
 
-	err = apiRegistrationClient.ApiregistrationV1().APIServices().Delete(context.TODO(), "v1.management.loft.sh", metav1.DeleteOptions{})
+	err = apiRegistrationClient.ApiregistrationV1().APIServices().Delete(context.TODO(), LoftAPIServiceName, metav1.DeleteOptions{})
 	if err != nil && kerrors.IsNotFound(err) == false {
 		return err
 	}
 
+	// wait until both are gone, otherwise a reinstall could fail because they still exist
+	err = wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		_, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), "loft", metav1.GetOptions{})
+		if err == nil {
+			return false, nil
+		} else if kerrors.IsNotFound(err) == false {
+			return false, err
+		}
+
+		_, err = apiRegistrationClient.ApiregistrationV1().APIServices().Get(context.TODO(), LoftAPIServiceName, metav1.GetOptions{})
+		if err == nil {
+			return false, nil
+		} else if kerrors.IsNotFound(err) == false {
+			return false, err
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "wait for the loft validating webhook and apiservice to be deleted")
+	}
+
 	loftClient, err := loftclientset.NewForConfig(restConfig)
 	if err != nil {
 		return err
@@ -433,16 +894,230 @@ func UninstallLoft(kubeClient kubernetes.Interface, restConfig *rest.Config, kub
 	return nil
 }
 
-func InstallIngressController(kubeClient kubernetes.Interface, kubeContext string, log log.Logger) error {
-	// first create an ingress controller
-	const (
-		YesOption = "Yes"
-		NoOption  = "No, I already have an ingress controller installed"
-	)
+// FindForeignResources returns the helm releases and workloads in the given namespace that do not belong
+// to the loft helm release
+func FindForeignResources(kubeClient kubernetes.Interface, namespace string) ([]string, error) {
+	foreign := []string{}
+	secrets, err := kubeClient.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: "owner=helm"})
+	if err != nil {
+		return nil, errors.Wrap(err, "list helm releases")
+	}
 
-	answer, err := log.Question(&survey.QuestionOptions{
+	releases := map[string]bool{}
+	for _, secret := range secrets.Items {
+		release := secret.Labels["name"]
+		if release == "" || release == "loft" || releases[release] {
+			continue
+		}
+
+		releases[release] = true
+		foreign = append(foreign, "helm release "+release)
+	}
+
+	deployments, err := kubeClient.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list deployments")
+	}
+	for _, deployment := range deployments.Items {
+		if isLoftResource(deployment.ObjectMeta) == false {
+			foreign = append(foreign, "deployment "+deployment.Name)
+		}
+	}
+
+	statefulSets, err := kubeClient.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list statefulsets")
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if isLoftResource(statefulSet.ObjectMeta) == false {
+			foreign = append(foreign, "statefulset "+statefulSet.Name)
+		}
+	}
+
+	daemonSets, err := kubeClient.AppsV1().DaemonSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list daemonsets")
+	}
+	for _, daemonSet := range daemonSets.Items {
+		if isLoftResource(daemonSet.ObjectMeta) == false {
+			foreign = append(foreign, "daemonset "+daemonSet.Name)
+		}
+	}
+
+	return foreign, nil
+}
+
+func isLoftResource(meta metav1.ObjectMeta) bool {
+	return meta.Labels["release"] == "loft" || meta.Annotations["meta.helm.sh/release-name"] == "loft"
+}
+
+// TerminatingGracePeriod is how long a namespace may be terminating before it is considered stuck
+const TerminatingGracePeriod = 2 * time.Minute
+
+// SpaceAccountLabel is the label kiosk sets on the namespaces of spaces
+const SpaceAccountLabel = "kiosk.sh/account"
+
+// StuckNamespace is a namespace that is terminating for longer than the TerminatingGracePeriod
+type StuckNamespace struct {
+	Name string
+	// Finalizers are the finalizers of the namespace itself
+	Finalizers []string
+	// Reasons are the messages of the namespace conditions that block the deletion,
+	// e.g. the finalizers of resources that are left in the namespace
+	Reasons []string
+}
+
+// EnsureImagePullSecret creates or updates the image pull secret with the given name from the
+// docker config.json at the given path. The namespace is created if it does not exist yet
+func EnsureImagePullSecret(kubeClient kubernetes.Interface, namespace, name, dockerConfigPath string) error {
+	dockerConfig, err := ioutil.ReadFile(dockerConfigPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	if err != nil && kerrors.IsAlreadyExists(err) == false {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: dockerConfig,
+		},
+	}
+	_, err = kubeClient.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		_, err = kubeClient.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	}
+
+	return err
+}
+
+// FindStuckNamespaces returns the loft namespace and the namespaces of spaces that are terminating for
+// longer than the TerminatingGracePeriod. Other namespaces of the cluster are ignored.
+func FindStuckNamespaces(kubeClient kubernetes.Interface, loftNamespace string) ([]StuckNamespace, error) {
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list namespaces")
+	}
+
+	stuck := []StuckNamespace{}
+	for _, namespace := range namespaces.Items {
+		if namespace.DeletionTimestamp == nil || time.Since(namespace.DeletionTimestamp.Time) < TerminatingGracePeriod {
+			continue
+		} else if _, isSpace := namespace.Labels[SpaceAccountLabel]; namespace.Name != loftNamespace && isSpace == false {
+			continue
+		}
+
+		stuckNamespace := StuckNamespace{
+			Name:       namespace.Name,
+			Finalizers: append([]string{}, namespace.Finalizers...),
+		}
+		for _, finalizer := range namespace.Spec.Finalizers {
+			stuckNamespace.Finalizers = append(stuckNamespace.Finalizers, string(finalizer))
+		}
+		for _, condition := range namespace.Status.Conditions {
+			if condition.Status == corev1.ConditionTrue && (condition.Type == corev1.NamespaceFinalizersRemaining || condition.Type == corev1.NamespaceContentRemaining) {
+				stuckNamespace.Reasons = append(stuckNamespace.Reasons, condition.Message)
+			}
+		}
+
+		stuck = append(stuck, stuckNamespace)
+	}
+
+	return stuck, nil
+}
+
+// RemoveNamespaceFinalizers removes the finalizers of the given namespace, so kubernetes deletes it
+// without waiting for the finalizers. Resources in the namespace that have finalizers themselves are
+// not cleaned up by their controllers then.
+func RemoveNamespaceFinalizers(kubeClient kubernetes.Interface, name string) error {
+	namespace, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if len(namespace.Finalizers) > 0 {
+		namespace.Finalizers = nil
+		namespace, err = kubeClient.CoreV1().Namespaces().Update(context.TODO(), namespace, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "remove namespace finalizers")
+		}
+	}
+
+	if len(namespace.Spec.Finalizers) > 0 {
+		namespace.Spec.Finalizers = nil
+		_, err = kubeClient.CoreV1().Namespaces().Finalize(context.TODO(), namespace, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "finalize namespace")
+		}
+	}
+
+	return nil
+}
+
+// knownIngressControllers are label selectors of the deployments of commonly used ingress controllers
+var knownIngressControllers = []string{
+	"app.kubernetes.io/name=ingress-nginx",
+	"app=nginx-ingress",
+	"app.kubernetes.io/name=traefik",
+	"app.kubernetes.io/name=haproxy-ingress",
+	"app.kubernetes.io/name=contour",
+	"app.kubernetes.io/name=kong",
+}
+
+// DetectIngressController returns a description of an ingress controller that already exists in the
+// cluster, either found via its ingress class or via the deployment of a known controller. If no
+// ingress controller is found, an empty string is returned
+func DetectIngressController(kubeClient kubernetes.Interface) (string, error) {
+	ingressClasses, err := kubeClient.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil && kerrors.IsNotFound(err) == false {
+		return "", errors.Wrap(err, "list ingress classes")
+	} else if err == nil && len(ingressClasses.Items) > 0 {
+		return fmt.Sprintf("ingress class %s (%s)", ingressClasses.Items[0].Name, ingressClasses.Items[0].Spec.Controller), nil
+	}
+
+	for _, labelSelector := range knownIngressControllers {
+		deployments, err := kubeClient.AppsV1().Deployments("").List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return "", errors.Wrap(err, "list deployments")
+		} else if len(deployments.Items) > 0 {
+			return fmt.Sprintf("deployment %s/%s", deployments.Items[0].Namespace, deployments.Items[0].Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+func InstallIngressController(kubeClient kubernetes.Interface, kubeContext string, log log.Logger) error {
+	// first create an ingress controller
+	const (
+		YesOption = "Yes"
+		NoOption  = "No, I already have an ingress controller installed"
+	)
+
+	// don't suggest a second ingress controller if there is one already
+	defaultOption := YesOption
+	existing, err := DetectIngressController(kubeClient)
+	if err != nil {
+		log.Warnf("Couldn't check for an existing ingress controller: %v", err)
+	} else if existing != "" {
+		log.Infof("Found an existing ingress controller: %s", existing)
+		defaultOption = NoOption
+	}
+
+	answer, err := log.Question(&survey.QuestionOptions{
 		Question:     "Ingress controller required. Should the nginx-ingress controller be installed?",
-		DefaultValue: YesOption,
+		DefaultValue: defaultOption,
 		Options: []string{
 			YesOption,
 			NoOption,
@@ -470,85 +1145,310 @@ func InstallIngressController(kubeClient kubernetes.Interface, kubeContext strin
 		}
 		log.WriteString("\n")
 		log.Infof("Executing command: helm %s\n", strings.Join(args, " "))
-		log.StartWait("Waiting for ingress controller deployment, this can take several minutes...")
-		output, err := exec.Command("helm", args...).CombinedOutput()
-		log.StopWait()
+		output, err := runHelmWithProgress(args, "Waiting for ingress controller deployment, this can take several minutes...", log)
 		if err != nil {
 			return fmt.Errorf("error during helm command: %s (%v)", string(output), err)
 		}
+		printHelmOutput(output, log)
 
-		list, err := kubeClient.CoreV1().Secrets("ingress-nginx").List(context.TODO(), metav1.ListOptions{
-			LabelSelector: "name=ingress-nginx,owner=helm,status=deployed",
-		})
+		err = MarkHelmReleaseAsLoftApp(kubeClient, "ingress-nginx", "ingress-nginx", "https://kubernetes.github.io/ingress-nginx")
 		if err != nil {
-			return err
+			log.Warnf("Couldn't mark the ingress-nginx release as loft app, so it won't show up in the loft app store: %v", err)
 		}
 
-		if len(list.Items) == 1 {
-			secret := list.Items[0]
-			originalSecret := secret.DeepCopy()
-			secret.Labels["loft.sh/app"] = "true"
-			if secret.Annotations == nil {
-				secret.Annotations = map[string]string{}
-			}
+		log.Done("Successfully installed ingress-nginx to your kubernetes cluster!")
+	}
 
-			secret.Annotations["loft.sh/url"] = "https://kubernetes.github.io/ingress-nginx"
-			originalJSON, err := json.Marshal(originalSecret)
-			if err != nil {
-				return err
-			}
-			modifiedJSON, err := json.Marshal(secret)
-			if err != nil {
-				return err
-			}
-			data, err := jsonpatch.CreateMergePatch(originalJSON, modifiedJSON)
-			if err != nil {
-				return err
-			}
-			_, err = kubeClient.CoreV1().Secrets(secret.Namespace).Patch(context.TODO(), secret.Name, types.MergePatchType, data, metav1.PatchOptions{})
-			if err != nil {
-				return err
-			}
+	return nil
+}
+
+// MarkHelmReleaseAsLoftApp marks the deployed helm release with the given name in the given namespace
+// as managed by the loft app store. This can also be used for releases that were installed outside of loft.
+// Because the release secret might not be visible right after the install, this waits shortly until exactly
+// one deployed release secret is found and returns an error otherwise.
+func MarkHelmReleaseAsLoftApp(kubeClient kubernetes.Interface, namespace, releaseName, repoURL string) error {
+	var list *corev1.SecretList
+	err := wait.PollImmediate(time.Second, time.Second*30, func() (bool, error) {
+		var err error
+		list, err = kubeClient.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: "name=" + releaseName + ",owner=helm,status=deployed",
+		})
+		if err != nil {
+			return false, err
 		}
 
-		log.Done("Successfully installed ingress-nginx to your kubernetes cluster!")
+		return len(list.Items) == 1, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("expected exactly one deployed release secret for release %s in namespace %s, but found %d", releaseName, namespace, len(list.Items))
+	} else if err != nil {
+		return err
 	}
 
-	return nil
+	return PatchSecretMetadata(kubeClient, namespace, list.Items[0].Name, map[string]string{
+		"loft.sh/app": "true",
+	}, map[string]string{
+		"loft.sh/url": repoURL,
+	})
+}
+
+// PatchSecretMetadata adds the given labels and annotations to the secret via a merge patch
+func PatchSecretMetadata(kubeClient kubernetes.Interface, namespace, name string, labels, annotations map[string]string) error {
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	originalSecret := secret.DeepCopy()
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		secret.Labels[k] = v
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	for k, v := range annotations {
+		secret.Annotations[k] = v
+	}
+
+	originalJSON, err := json.Marshal(originalSecret)
+	if err != nil {
+		return err
+	}
+	modifiedJSON, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+	data, err := jsonpatch.CreateMergePatch(originalJSON, modifiedJSON)
+	if err != nil {
+		return err
+	}
+
+	_, err = kubeClient.CoreV1().Secrets(namespace).Patch(context.TODO(), name, types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+// printHelmOutput prints the output of a successful helm command if the log verbosity is high enough
+func printHelmOutput(output []byte, logger log.Logger) {
+	if logger.GetVerbosity() >= log.VerbosityDetailed {
+		logger.Debugf("Helm output:\n%s", string(output))
+	}
 }
 
-func UpgradeLoft(kubeContext, namespace string, extraArgs []string, log log.Logger) error {
+// redactHelmArgs joins the helm arguments for logging and hides the helm repository password
+func redactHelmArgs(args []string) string {
+	redacted := append([]string{}, args...)
+	for i := range redacted {
+		if redacted[i] == "--password" && i+1 < len(redacted) {
+			redacted[i+1] = "********"
+		}
+	}
+
+	return strings.Join(redacted, " ")
+}
+
+// DefaultChartName is the name of the loft helm chart
+const DefaultChartName = "loft"
+
+// DefaultChartRepo is the public helm repository the loft chart is installed from
+const DefaultChartRepo = "https://charts.loft.sh/"
+
+func UpgradeLoft(chartName, chartRepo, kubeContext, namespace string, extraArgs []string, log log.Logger) error {
 	// now we install loft
 	args := []string{
 		"upgrade",
 		"loft",
-		"loft",
+		chartName,
 		"--install",
 		"--create-namespace",
 		"--repository-config=''",
-		"--repo",
-		"https://charts.loft.sh/",
 		"--kube-context",
 		kubeContext,
 		"--namespace",
 		namespace,
 	}
+	if chartRepo != "" {
+		args = append(args, "--repo", chartRepo)
+	}
 	args = append(args, extraArgs...)
 
 	log.WriteString("\n")
-	log.Infof("Executing command: helm %s\n", strings.Join(args, " "))
-	log.StartWait("Waiting for helm command, this can take up to several minutes...")
-	output, err := exec.Command("helm", args...).CombinedOutput()
-	log.StopWait()
+	log.Infof("Executing command: helm %s\n", redactHelmArgs(args))
+	output, err := runHelmWithProgress(args, "Waiting for helm command, this can take up to several minutes...", log)
 	if err != nil {
 		return fmt.Errorf("error during helm command: %s (%v)", string(output), err)
 	}
+	printHelmOutput(output, log)
 
 	log.Done("Successfully deployed loft to your kubernetes cluster!")
 	log.WriteString("\n")
 	return nil
 }
 
+// PrintHelmValues runs a helm dry run with the given arguments and prints the resulting merged values
+// that helm would use for the loft release, without changing anything in the cluster
+func PrintHelmValues(chartName, chartRepo, kubeContext, namespace string, extraArgs []string, log log.Logger) error {
+	args := []string{
+		"upgrade",
+		"loft",
+		chartName,
+		"--install",
+		"--dry-run",
+		"--output",
+		"json",
+		"--repository-config=''",
+		"--kube-context",
+		kubeContext,
+		"--namespace",
+		namespace,
+	}
+	if chartRepo != "" {
+		args = append(args, "--repo", chartRepo)
+	}
+	args = append(args, extraArgs...)
+
+	log.Infof("Executing command: helm %s\n", redactHelmArgs(args))
+	log.StartWait("Rendering loft helm values...")
+	output, err := exec.Command("helm", args...).Output()
+	log.StopWait()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("error during helm command: %s (%v)", string(exitError.Stderr), err)
+		}
+
+		return fmt.Errorf("error during helm command: %v", err)
+	}
+
+	release := struct {
+		Config map[string]interface{} `json:"config,omitempty"`
+	}{}
+	err = json.Unmarshal(output, &release)
+	if err != nil {
+		return errors.Wrap(err, "parse helm output")
+	}
+
+	out, err := yaml.Marshal(release.Config)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Merged helm values for the loft release:")
+	log.WriteString("\n" + string(out) + "\n")
+	return nil
+}
+
+// knownChartValues are the top level values of the loft helm chart
+var knownChartValues = []string{
+	"admin",
+	"affinity",
+	"agentOnly",
+	"apiservice",
+	"audit",
+	"certIssuer",
+	"cluster",
+	"config",
+	"env",
+	"image",
+	"imagePullSecrets",
+	"ingress",
+	"livenessProbe",
+	"nodeSelector",
+	"podAnnotations",
+	"podLabels",
+	"product",
+	"readinessProbe",
+	"replicaCount",
+	"resources",
+	"service",
+	"serviceAccount",
+	"storage",
+	"tag",
+	"tls",
+	"tolerations",
+	"volumeMounts",
+	"volumes",
+	"webhook",
+}
+
+// ValidateHelmValuesFile checks that the given values file is valid yaml and warns about
+// top level values that are unknown to the loft chart, which are most likely typos
+func ValidateHelmValuesFile(path string, log log.Logger) error {
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "read values file")
+	}
+
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(out, &values)
+	if err != nil {
+		return fmt.Errorf("values file %s is not valid yaml: %v", path, err)
+	}
+
+	for key := range values {
+		known := false
+		for _, knownValue := range knownChartValues {
+			if key == knownValue {
+				known = true
+				break
+			}
+		}
+		if known {
+			continue
+		}
+
+		closest := util.ClosestMatch(key, knownChartValues, 3)
+		if closest != "" {
+			log.Warnf("Unknown value '%s' in values file %s, did you mean '%s'?", key, path, closest)
+		} else {
+			log.Warnf("Unknown value '%s' in values file %s", key, path)
+		}
+	}
+
+	return nil
+}
+
+// AddHelmRepos adds the given helm repositories in the form name=url to a temporary helm repository
+// config, so that chart dependencies from these repositories can be resolved during the installation.
+// It returns the helm arguments to use the repositories and a function that removes them again.
+func AddHelmRepos(repos []string, log log.Logger) ([]string, func(), error) {
+	repoConfig, err := ioutil.TempFile("", "loft-repositories-*.yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+	repoConfig.Close()
+
+	cleanup := func() {
+		_ = os.Remove(repoConfig.Name())
+	}
+	for _, repo := range repos {
+		splitted := strings.SplitN(repo, "=", 2)
+		if len(splitted) != 2 || splitted[0] == "" || splitted[1] == "" {
+			cleanup()
+			return nil, nil, fmt.Errorf("invalid helm repository %s, expected the form name=url", repo)
+		}
+
+		args := []string{
+			"repo",
+			"add",
+			splitted[0],
+			splitted[1],
+			"--repository-config",
+			repoConfig.Name(),
+		}
+		log.Infof("Executing command: helm %s\n", strings.Join(args, " "))
+		output, err := exec.Command("helm", args...).CombinedOutput()
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("error during helm command: %s (%v)", string(output), err)
+		}
+		printHelmOutput(output, log)
+	}
+
+	return []string{"--repository-config", repoConfig.Name(), "--dependency-update"}, cleanup, nil
+}
+
 func defaultHelmValues(password, email, version, values string, extraArgs []string) []string {
 	// now we install loft
 	args := []string{
@@ -571,29 +1471,36 @@ func defaultHelmValues(password, email, version, values string, extraArgs []stri
 	return args
 }
 
-func InstallLoftRemote(kubeContext, namespace, password, email, version, values, host string, log log.Logger) error {
-	extraArgs := defaultHelmValues(password, email, version, values, []string{
+// RemoteHelmArgs returns the helm arguments to install loft reachable at the given host
+func RemoteHelmArgs(password, email, version, values, host string, helmArgs []string) []string {
+	return defaultHelmValues(password, email, version, values, append(append([]string{
 		"--set",
 		"ingress.enabled=true",
+	}, IngressHostValues(host)...), helmArgs...))
+}
+
+// LocalHelmArgs returns the helm arguments to install loft without an ingress
+func LocalHelmArgs(password, email, version, values string, helmArgs []string) []string {
+	return defaultHelmValues(password, email, version, values, append([]string{
 		"--set",
-		"ingress.host=" + host,
-	})
+		"ingress.enabled=false",
+	}, helmArgs...))
+}
 
-	return UpgradeLoft(kubeContext, namespace, extraArgs, log)
+func InstallLoftRemote(chartName, chartRepo, kubeContext, namespace, password, email, version, values, host string, helmArgs []string, log log.Logger) error {
+	extraArgs := RemoteHelmArgs(password, email, version, values, host, helmArgs)
+	return UpgradeLoft(chartName, chartRepo, kubeContext, namespace, extraArgs, log)
 }
 
-func InstallLoftLocally(kubeContext, namespace, password, email, version, values string, log log.Logger) error {
+func InstallLoftLocally(chartName, chartRepo, kubeContext, namespace, password, email, version, values string, helmArgs []string, log log.Logger) error {
 	log.WriteString("\n")
 	log.Info("This will install loft without an externally reachable URL and instead use port-forwarding to connect to loft")
 	log.WriteString("\n")
 
 	// deploy loft into the cluster
-	extraArgs := defaultHelmValues(password, email, version, values, []string{
-		"--set",
-		"ingress.enabled=false",
-	})
+	extraArgs := LocalHelmArgs(password, email, version, values, helmArgs)
 
-	return UpgradeLoft(kubeContext, namespace, extraArgs, log)
+	return UpgradeLoft(chartName, chartRepo, kubeContext, namespace, extraArgs, log)
 }
 
 func EnsureAdminPassword(kubeClient kubernetes.Interface, restConfig *rest.Config, password string, log log.Logger) error {
@@ -640,6 +1547,125 @@ func EnsureAdminPassword(kubeClient kubernetes.Interface, restConfig *rest.Confi
 	return nil
 }
 
+// CreateAdminServiceAccountToken creates a service account with cluster-admin access in the given
+// namespace and returns its token. The namespace is created if it does not exist.
+func CreateAdminServiceAccountToken(kubeClient kubernetes.Interface, namespace, serviceAccount string) (string, error) {
+	// make sure namespace exists
+	_, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		if kerrors.IsAlreadyExists(err) == false {
+			return "", err
+		}
+	}
+
+	// create service account
+	_, err = kubeClient.CoreV1().ServiceAccounts(namespace).Create(context.TODO(), &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: serviceAccount,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		if kerrors.IsAlreadyExists(err) == false {
+			return "", err
+		}
+	}
+
+	// create clusterrolebinding
+	kubeClient.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: serviceAccount + "-binding",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      serviceAccount,
+				Namespace: namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+	}, metav1.CreateOptions{})
+
+	// wait for secret
+	token := []byte{}
+	err = wait.Poll(time.Millisecond*250, time.Minute*2, func() (bool, error) {
+		sa, err := kubeClient.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), serviceAccount, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "retrieve service account")
+		} else if len(sa.Secrets) == 0 {
+			return false, nil
+		}
+
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), sa.Secrets[0].Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "get service account secret")
+		}
+
+		ok := false
+		token, ok = secret.Data["token"]
+		if !ok {
+			return false, fmt.Errorf("service account secret has unexpected contents")
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(token), nil
+}
+
+// InstallLoftAgent installs only the loft agent into the cluster of the given kube context
+func InstallLoftAgent(chartName, chartRepo, kubeContext, namespace, version string, extraArgs []string, log log.Logger) error {
+	args := []string{
+		"--set",
+		"agentOnly=true",
+	}
+	if version != "" {
+		args = append(args, "--version", version)
+	}
+
+	return UpgradeLoft(chartName, chartRepo, kubeContext, namespace, append(args, extraArgs...), log)
+}
+
+// CreateAdminAccessKey creates a new access key for the loft admin user and returns it
+func CreateAdminAccessKey(restConfig *rest.Config) (string, error) {
+	loftClient, err := loftclientset.NewForConfig(restConfig)
+	if err != nil {
+		return "", err
+	}
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return "", errors.Wrap(err, "generate access key")
+	}
+
+	accessKey, err := loftClient.StorageV1().AccessKeys().Create(context.TODO(), &storagev1.AccessKey{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "loft-start-",
+		},
+		Spec: storagev1.AccessKeySpec{
+			DisplayName: "loft start",
+			User:        "admin",
+			Key:         hex.EncodeToString(key),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return accessKey.Spec.Key, nil
+}
+
 func IsLoftInstalledLocally(kubeClient kubernetes.Interface, namespace string) bool {
 	_, err := kubeClient.NetworkingV1().Ingresses(namespace).Get(context.TODO(), "loft-ingress", metav1.GetOptions{})
 	if err != nil && kerrors.IsNotFound(err) == false {
@@ -0,0 +1,168 @@
+package clihelper
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func runningContainer(name string, ready bool) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:  name,
+		Ready: ready,
+		State: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		},
+	}
+}
+
+func waitingContainer(name, reason string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name: name,
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: reason},
+		},
+	}
+}
+
+func terminatedContainer(name string, exitCode int32) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name: name,
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+		},
+	}
+}
+
+func newPod(containers []string, initContainers []string, statuses []corev1.ContainerStatus, initStatuses []corev1.ContainerStatus) *corev1.Pod {
+	pod := &corev1.Pod{}
+	for _, name := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: name})
+	}
+	for _, name := range initContainers {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{Name: name})
+	}
+	pod.Status.ContainerStatuses = statuses
+	pod.Status.InitContainerStatuses = initStatuses
+	return pod
+}
+
+type getPodStateTestCase struct {
+	name string
+	pod  *corev1.Pod
+
+	expectedReady   bool
+	expectedFailed  string
+	expectedWaiting string
+}
+
+func TestGetPodState(t *testing.T) {
+	testCases := []getPodStateTestCase{
+		{
+			name:          "Single ready container",
+			pod:           newPod([]string{"manager"}, nil, []corev1.ContainerStatus{runningContainer("manager", true)}, nil),
+			expectedReady: true,
+		},
+		{
+			name:          "No container statuses yet",
+			pod:           newPod([]string{"manager"}, nil, nil, nil),
+			expectedReady: false,
+		},
+		{
+			name: "Ready main container with unready sidecar",
+			pod: newPod([]string{"manager", "sidecar"}, nil, []corev1.ContainerStatus{
+				runningContainer("manager", true),
+				runningContainer("sidecar", false),
+			}, nil),
+			expectedReady: false,
+		},
+		{
+			name: "Ready sidecar with unready main container",
+			pod: newPod([]string{"manager", "sidecar"}, nil, []corev1.ContainerStatus{
+				runningContainer("sidecar", true),
+				runningContainer("manager", false),
+			}, nil),
+			expectedReady: false,
+		},
+		{
+			name: "Ready main container and sidecar",
+			pod: newPod([]string{"manager", "sidecar"}, nil, []corev1.ContainerStatus{
+				runningContainer("sidecar", true),
+				runningContainer("manager", true),
+			}, nil),
+			expectedReady: true,
+		},
+		{
+			name: "Sidecar status still missing",
+			pod: newPod([]string{"manager", "sidecar"}, nil, []corev1.ContainerStatus{
+				runningContainer("manager", true),
+			}, nil),
+			expectedReady: false,
+		},
+		{
+			name: "Main container missing",
+			pod: newPod([]string{"sidecar"}, nil, []corev1.ContainerStatus{
+				runningContainer("sidecar", true),
+			}, nil),
+			expectedReady: false,
+		},
+		{
+			name: "Crash looping sidecar",
+			pod: newPod([]string{"manager", "sidecar"}, nil, []corev1.ContainerStatus{
+				runningContainer("manager", true),
+				waitingContainer("sidecar", "CrashLoopBackOff"),
+			}, nil),
+			expectedFailed: "sidecar",
+		},
+		{
+			name: "Waiting main container",
+			pod: newPod([]string{"manager", "sidecar"}, nil, []corev1.ContainerStatus{
+				waitingContainer("manager", "ContainerCreating"),
+				runningContainer("sidecar", true),
+			}, nil),
+			expectedWaiting: "manager",
+		},
+		{
+			name: "Running init container",
+			pod: newPod([]string{"manager"}, []string{"init"}, nil, []corev1.ContainerStatus{
+				runningContainer("init", false),
+			}),
+			expectedReady: false,
+		},
+		{
+			name: "Failed init container",
+			pod: newPod([]string{"manager"}, []string{"init"}, nil, []corev1.ContainerStatus{
+				terminatedContainer("init", 1),
+			}),
+			expectedFailed: "init",
+		},
+		{
+			name: "Completed init container",
+			pod: newPod([]string{"manager"}, []string{"init"}, []corev1.ContainerStatus{
+				runningContainer("manager", true),
+			}, []corev1.ContainerStatus{
+				terminatedContainer("init", 0),
+			}),
+			expectedReady: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		state := getPodState(testCase.pod, "manager")
+		assert.Equal(t, testCase.expectedReady, state.Ready, "Unexpected ready state in test case %s", testCase.name)
+
+		failed := ""
+		if state.Failed != nil {
+			failed = state.Failed.Name
+		}
+		assert.Equal(t, testCase.expectedFailed, failed, "Unexpected failed container in test case %s", testCase.name)
+
+		waiting := ""
+		if state.Waiting != nil {
+			waiting = state.Waiting.Name
+		}
+		assert.Equal(t, testCase.expectedWaiting, waiting, "Unexpected waiting container in test case %s", testCase.name)
+	}
+}
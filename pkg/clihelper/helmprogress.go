@@ -0,0 +1,73 @@
+package clihelper
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/loft-sh/loftctl/pkg/log"
+)
+
+// helm prints these lines with --debug while it waits for the release resources
+var (
+	helmBeginWaitRegex = regexp.MustCompile(`beginning wait for (\d+) resources`)
+	helmNotReadyRegex  = regexp.MustCompile(`(\w+) is not ready: (\S+)`)
+)
+
+// runHelmWithProgress runs helm with --debug and updates the wait message of the logger with the
+// resource helm is currently waiting for. It returns the combined output of helm.
+func runHelmWithProgress(args []string, message string, log log.Logger) ([]byte, error) {
+	output := &bytes.Buffer{}
+	progress := &helmProgressWriter{
+		message: message,
+		log:     log,
+	}
+
+	log.StartWait(message)
+	defer log.StopWait()
+
+	helmCmd := exec.Command("helm", append(args, "--debug")...)
+	writer := io.MultiWriter(output, progress)
+	helmCmd.Stdout = writer
+	helmCmd.Stderr = writer
+	err := helmCmd.Run()
+	return output.Bytes(), err
+}
+
+// helmProgressWriter parses the helm debug output line by line and updates the wait message
+type helmProgressWriter struct {
+	message string
+	log     log.Logger
+
+	line []byte
+}
+
+func (w *helmProgressWriter) Write(p []byte) (int, error) {
+	w.line = append(w.line, p...)
+	for {
+		index := bytes.IndexByte(w.line, '\n')
+		if index == -1 {
+			return len(p), nil
+		}
+
+		w.update(string(w.line[:index]))
+		w.line = w.line[index+1:]
+	}
+}
+
+func (w *helmProgressWriter) update(line string) {
+	message := ""
+	if matches := helmNotReadyRegex.FindStringSubmatch(line); matches != nil {
+		message = "Waiting for " + matches[1] + " " + strings.TrimSuffix(matches[2], ".") + " to become ready..."
+	} else if matches := helmBeginWaitRegex.FindStringSubmatch(line); matches != nil {
+		message = "Waiting for " + matches[1] + " resources to become ready..."
+	}
+	if message == "" || message == w.message {
+		return
+	}
+
+	w.message = message
+	w.log.StartWait(message)
+}
@@ -0,0 +1,105 @@
+package clihelper
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// manifestFieldManager is the field manager loftctl uses for server side apply
+const manifestFieldManager = "loftctl"
+
+// ApplyManifests applies all objects of the given multi document yaml file via server side apply
+// and returns a description of each applied object in the form kind/namespace/name
+func ApplyManifests(restConfig *rest.Config, path string) ([]string, error) {
+	objects, err := readManifests(path)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []string{}
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return applied, errors.Wrapf(err, "find resource for %s", gvk.Kind)
+		}
+
+		out, err := obj.MarshalJSON()
+		if err != nil {
+			return applied, err
+		}
+
+		name := strings.ToLower(gvk.Kind) + "/" + obj.GetName()
+		resourceClient := dynamicClient.Resource(mapping.Resource)
+		force := true
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace("default")
+			}
+
+			name = strings.ToLower(gvk.Kind) + "/" + obj.GetNamespace() + "/" + obj.GetName()
+			_, err = resourceClient.Namespace(obj.GetNamespace()).Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, out, metav1.PatchOptions{FieldManager: manifestFieldManager, Force: &force})
+		} else {
+			_, err = resourceClient.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, out, metav1.PatchOptions{FieldManager: manifestFieldManager, Force: &force})
+		}
+		if err != nil {
+			return applied, errors.Wrapf(err, "apply %s", name)
+		}
+
+		applied = append(applied, name)
+	}
+
+	return applied, nil
+}
+
+// readManifests decodes all non empty documents of the given yaml file
+func readManifests(path string) ([]*unstructured.Unstructured, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	objects := []*unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err = decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "decode %s", path)
+		} else if len(obj.Object) == 0 {
+			continue
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
@@ -1,6 +1,8 @@
 package kube
 
 import (
+	"github.com/loft-sh/loftctl/pkg/log"
+
 	kioskclient "github.com/loft-sh/agentapi/pkg/client/kiosk/clientset_generated/clientset"
 	agentloftclient "github.com/loft-sh/agentapi/pkg/client/loft/clientset_generated/clientset"
 	loftclient "github.com/loft-sh/api/pkg/client/clientset_generated/clientset"
@@ -19,6 +21,8 @@ type Interface interface {
 }
 
 func NewForConfig(c *rest.Config) (Interface, error) {
+	c = WithRequestTracing(c, log.GetInstance())
+
 	kubeClient, err := kubernetes.NewForConfig(c)
 	if err != nil {
 		return nil, errors.Wrap(err, "create kube client")
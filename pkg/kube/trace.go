@@ -0,0 +1,41 @@
+package kube
+
+import (
+	"net/http"
+
+	"github.com/loft-sh/loftctl/pkg/log"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// WithRequestTracing returns a copy of the given rest config that logs the kubernetes api
+// requests depending on the verbosity of the logger
+func WithRequestTracing(config *rest.Config, logger log.Logger) *rest.Config {
+	verbosity := logger.GetVerbosity()
+	if verbosity < log.VerbosityRequests {
+		return config
+	}
+
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		if verbosity >= log.VerbosityClientGo {
+			rt = transport.NewDebuggingRoundTripper(rt, transport.DebugURLTiming, transport.DebugResponseStatus)
+		}
+
+		return &tracingRoundTripper{
+			delegate: rt,
+			log:      logger,
+		}
+	})
+	return config
+}
+
+type tracingRoundTripper struct {
+	delegate http.RoundTripper
+	log      log.Logger
+}
+
+func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.log.Debugf("%s %s", req.Method, req.URL.Path)
+	return t.delegate.RoundTrip(req)
+}
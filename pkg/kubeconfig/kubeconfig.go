@@ -1,7 +1,9 @@
 package kubeconfig
 
 import (
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -72,6 +74,28 @@ func CurrentContext() (string, error) {
 	return config.CurrentContext, nil
 }
 
+// ContextExists checks if a context with the given name exists in the kube config
+func ContextExists(contextName string) (bool, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := config.Contexts[contextName]
+	return ok, nil
+}
+
+// UseContext sets the context with the given name as current context in the kube config
+func UseContext(contextName string) error {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return err
+	}
+
+	config.CurrentContext = contextName
+	return clientcmd.ModifyConfig(clientcmd.NewDefaultClientConfigLoadingRules(), config, false)
+}
+
 // DeleteContext deletes the context with the given name from the kube config
 func DeleteContext(contextName string) error {
 	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
@@ -95,6 +119,42 @@ func DeleteContext(contextName string) error {
 	return clientcmd.ModifyConfig(clientcmd.NewDefaultClientConfigLoadingRules(), config, false)
 }
 
+// WriteImpersonatedKubeConfig writes a temporary kube config that uses the given context and impersonates
+// the given user and groups, so that external tools like helm and kubectl run as the same identity.
+// The caller is responsible to delete the returned file.
+func WriteImpersonatedKubeConfig(config api.Config, contextName, user string, groups []string) (string, error) {
+	context, ok := config.Contexts[contextName]
+	if !ok || context == nil {
+		return "", fmt.Errorf("kube context %s does not exist", contextName)
+	}
+
+	authInfo := api.NewAuthInfo()
+	if config.AuthInfos[context.AuthInfo] != nil {
+		authInfo = config.AuthInfos[context.AuthInfo].DeepCopy()
+	}
+	authInfo.Impersonate = user
+	authInfo.ImpersonateGroups = groups
+
+	config.AuthInfos = map[string]*api.AuthInfo{context.AuthInfo: authInfo}
+	config.Contexts = map[string]*api.Context{contextName: context}
+	config.Clusters = map[string]*api.Cluster{context.Cluster: config.Clusters[context.Cluster]}
+	config.CurrentContext = contextName
+
+	tempFile, err := ioutil.TempFile("", "loft-kubeconfig-")
+	if err != nil {
+		return "", err
+	}
+	_ = tempFile.Close()
+
+	err = clientcmd.WriteToFile(config, tempFile.Name())
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+		return "", err
+	}
+
+	return tempFile.Name(), nil
+}
+
 func updateKubeConfig(contextName string, cluster *api.Cluster, authInfo *api.AuthInfo, namespaceName string, setActive bool) error {
 	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
 	if err != nil {
@@ -171,6 +231,11 @@ func PrintKubeConfigTo(options ContextOptions, writer io.Writer) error {
 
 // PrintTokenKubeConfig writes the kube config to the os.Stdout
 func PrintTokenKubeConfig(restConfig *rest.Config, token string) error {
+	return PrintTokenKubeConfigTo(restConfig, token, os.Stdout)
+}
+
+// PrintTokenKubeConfigTo writes a kube config for the cluster of the rest config that uses the given token
+func PrintTokenKubeConfigTo(restConfig *rest.Config, token string, writer io.Writer) error {
 	contextName := "default"
 	cluster := api.NewCluster()
 	cluster.Server = restConfig.Host
@@ -182,7 +247,7 @@ func PrintTokenKubeConfig(restConfig *rest.Config, token string) error {
 	authInfo := api.NewAuthInfo()
 	authInfo.Token = token
 
-	return printKubeConfigTo(contextName, cluster, authInfo, "", os.Stdout)
+	return printKubeConfigTo(contextName, cluster, authInfo, "", writer)
 }
 
 func createContext(options ContextOptions) (string, *api.Cluster, *api.AuthInfo, error) {
@@ -95,6 +95,15 @@ func (d *DiscardLogger) SetLevel(level logrus.Level) {}
 // GetLevel implements logger interface
 func (d *DiscardLogger) GetLevel() logrus.Level { return logrus.FatalLevel }
 
+// SetVerbosity implements logger interface
+func (d *DiscardLogger) SetVerbosity(verbosity int) {}
+
+// GetVerbosity implements logger interface
+func (d *DiscardLogger) GetVerbosity() int { return 0 }
+
+// SetPlain implements logger interface
+func (d *DiscardLogger) SetPlain(plain bool) {}
+
 // Write implements logger interface
 func (d *DiscardLogger) Write(message []byte) (int, error) {
 	return len(message), nil
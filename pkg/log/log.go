@@ -1,6 +1,7 @@
 package log
 
 import (
+	"io"
 	"strings"
 
 	"github.com/loft-sh/loftctl/pkg/survey"
@@ -21,14 +22,25 @@ func GetInstance() Logger {
 	return defaultLog
 }
 
+// NewLogger creates a logger that writes to the given writers instead of stdout and stderr, e.g. to
+// capture the output when loftctl is embedded. Wait messages are printed as info messages instead of a spinner
+func NewLogger(out io.Writer, errOut io.Writer, level logrus.Level) Logger {
+	return &stdoutLogger{
+		survey: survey.NewSurvey(),
+		level:  level,
+		out:    out,
+		errOut: errOut,
+	}
+}
+
 // SetInstance sets the default logger instance
 func SetInstance(logger Logger) {
 	defaultLog = logger
 }
 
 // WriteColored writes a message in color
-func writeColored(message string, color string) {
-	defaultLog.Write([]byte(ansi.Color(message, color)))
+func writeColored(s Logger, message string, color string) {
+	s.Write([]byte(ansi.Color(message, color)))
 }
 
 //SetFakePrintTable is a testing tool that allows overwriting the function PrintTable
@@ -45,6 +57,21 @@ func PrintTable(s Logger, header []string, values [][]string) {
 		return
 	}
 
+	printTable(s, header, values, true)
+}
+
+// PrintTableWithoutHeader prints a table with string values but omits the header row,
+// which makes the output easier to consume for tools like awk or cut
+func PrintTableWithoutHeader(s Logger, header []string, values [][]string) {
+	if fakePrintTable != nil {
+		fakePrintTable(s, nil, values)
+		return
+	}
+
+	printTable(s, header, values, false)
+}
+
+func printTable(s Logger, header []string, values [][]string, printHeader bool) {
 	columnLengths := make([]int, len(header))
 
 	for k, v := range header {
@@ -60,23 +87,25 @@ func PrintTable(s Logger, header []string, values [][]string) {
 		}
 	}
 
-	s.Write([]byte("\n"))
+	if printHeader {
+		s.Write([]byte("\n"))
 
-	// Print Header
-	for key, value := range header {
-		writeColored(" "+value+"  ", "green+b")
+		// Print Header
+		for key, value := range header {
+			writeColored(s, " "+value+"  ", "green+b")
 
-		padding := columnLengths[key] - len(value)
+			padding := columnLengths[key] - len(value)
 
-		if padding > 0 {
-			s.Write([]byte(strings.Repeat(" ", padding)))
+			if padding > 0 {
+				s.Write([]byte(strings.Repeat(" ", padding)))
+			}
 		}
-	}
 
-	s.Write([]byte("\n"))
+		s.Write([]byte("\n"))
 
-	if len(values) == 0 {
-		s.Write([]byte(" No entries found\n"))
+		if len(values) == 0 {
+			s.Write([]byte(" No entries found\n"))
+		}
 	}
 
 	// Print Values
@@ -94,5 +123,7 @@ func PrintTable(s Logger, header []string, values [][]string) {
 		s.Write([]byte("\n"))
 	}
 
-	s.Write([]byte("\n"))
+	if printHeader {
+		s.Write([]byte("\n"))
+	}
 }
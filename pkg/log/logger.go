@@ -19,6 +19,15 @@ const (
 	doneFn
 )
 
+const (
+	// VerbosityDetailed additionally logs details such as the full helm command lines and output
+	VerbosityDetailed = 1
+	// VerbosityRequests additionally logs every request made against the kubernetes api
+	VerbosityRequests = 2
+	// VerbosityClientGo additionally enables the request logging of client-go itself
+	VerbosityClientGo = 3
+)
+
 // Logger defines the common logging interface
 type Logger interface {
 	Debug(args ...interface{})
@@ -58,4 +67,11 @@ type Logger interface {
 
 	SetLevel(level logrus.Level)
 	GetLevel() logrus.Level
+
+	SetVerbosity(verbosity int)
+	GetVerbosity() int
+
+	// SetPlain replaces the spinner of wait messages with plain lines that show the elapsed time
+	// and disables colors
+	SetPlain(plain bool)
 }
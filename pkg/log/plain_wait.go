@@ -0,0 +1,74 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// plainWaitInterval is the interval in which a plain wait message reports that it is still running
+const plainWaitInterval = time.Second * 10
+
+// plainWait is a running wait message of a logger in plain mode
+type plainWait struct {
+	message  string
+	started  time.Time
+	stopChan chan struct{}
+}
+
+// startPlainWait prints the message and then periodically how long the wait is already running
+func (s *stdoutLogger) startPlainWait(message string) {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	if s.plainWait != nil {
+		if s.plainWait.message == message {
+			return
+		}
+
+		s.finishPlainWait()
+	}
+	if s.level < logrus.InfoLevel {
+		return
+	}
+
+	wait := &plainWait{
+		message:  message,
+		started:  time.Now(),
+		stopChan: make(chan struct{}),
+	}
+	s.plainWait = wait
+	s.writeMessage(infoFn, message+"\n")
+
+	go func() {
+		for {
+			select {
+			case <-wait.stopChan:
+				return
+			case <-time.After(plainWaitInterval):
+				s.logMutex.Lock()
+				if s.plainWait == wait {
+					s.writeMessage(infoFn, fmt.Sprintf("Still working on: %s (%ds elapsed)\n", message, int(time.Since(wait.started).Seconds())))
+				}
+				s.logMutex.Unlock()
+			}
+		}
+	}()
+}
+
+func (s *stdoutLogger) stopPlainWait() {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	if s.plainWait != nil {
+		s.finishPlainWait()
+	}
+}
+
+// finishPlainWait stops the running wait message and prints how long it took. Expects the log mutex to be held
+func (s *stdoutLogger) finishPlainWait() {
+	close(s.plainWait.stopChan)
+	s.writeMessage(infoFn, fmt.Sprintf("Finished: %s (%ds elapsed)\n", s.plainWait.message, int(time.Since(s.plainWait.started).Seconds())))
+	s.plainWait = nil
+}
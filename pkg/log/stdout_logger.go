@@ -17,13 +17,22 @@ var stdout = goansi.NewAnsiStdout()
 var stderr = goansi.NewAnsiStderr()
 
 type stdoutLogger struct {
-	logMutex sync.Mutex
-	level    logrus.Level
+	logMutex  sync.Mutex
+	level     logrus.Level
+	verbosity int
 
 	loadingText *loadingText
 
+	// plain prints wait messages as lines with the elapsed time instead of a spinner
+	plain     bool
+	plainWait *plainWait
+
 	survey     survey.Survey
 	fileLogger Logger
+
+	// out and errOut replace stdout and stderr if set
+	out    io.Writer
+	errOut io.Writer
 }
 
 type fnTypeInformation struct {
@@ -84,6 +93,17 @@ var fnTypeInformationMap = map[logFunctionType]*fnTypeInformation{
 	},
 }
 
+// stream returns the writer messages of the given type are written to
+func (s *stdoutLogger) stream(fnType logFunctionType) io.Writer {
+	if s.out == nil {
+		return fnTypeInformationMap[fnType].stream
+	} else if fnTypeInformationMap[fnType].stream == stderr {
+		return s.errOut
+	}
+
+	return s.out
+}
+
 func (s *stdoutLogger) writeMessage(fnType logFunctionType, message string) {
 	fnInformation := fnTypeInformationMap[fnType]
 	if s.level >= fnInformation.logLevel {
@@ -91,8 +111,13 @@ func (s *stdoutLogger) writeMessage(fnType logFunctionType, message string) {
 			s.loadingText.Stop()
 		}
 
-		fnInformation.stream.Write([]byte(ansi.Color(fnInformation.tag, fnInformation.color)))
-		fnInformation.stream.Write([]byte(message))
+		stream := s.stream(fnType)
+		if s.plain {
+			stream.Write([]byte(fnInformation.tag))
+		} else {
+			stream.Write([]byte(ansi.Color(fnInformation.tag, fnInformation.color)))
+		}
+		stream.Write([]byte(message))
 
 		if s.loadingText != nil && fnType != fatalFn {
 			s.loadingText.Start()
@@ -152,7 +177,10 @@ func (s *stdoutLogger) writeMessageToFileLoggerf(fnType logFunctionType, format
 
 // StartWait prints a wait message until StopWait is called
 func (s *stdoutLogger) StartWait(message string) {
-	if !tty.IsTerminalIn() {
+	if s.plain {
+		s.startPlainWait(message)
+		return
+	} else if !tty.IsTerminalIn() || s.out != nil {
 		s.Info(message)
 		return
 	}
@@ -181,7 +209,10 @@ func (s *stdoutLogger) StartWait(message string) {
 
 // StartWait prints a wait message until StopWait is called
 func (s *stdoutLogger) StopWait() {
-	if !tty.IsTerminalIn() {
+	if s.plain {
+		s.stopPlainWait()
+		return
+	} else if !tty.IsTerminalIn() {
 		return
 	}
 
@@ -391,6 +422,31 @@ func (s *stdoutLogger) GetLevel() logrus.Level {
 	return s.level
 }
 
+func (s *stdoutLogger) SetVerbosity(verbosity int) {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	s.verbosity = verbosity
+}
+
+func (s *stdoutLogger) SetPlain(plain bool) {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	s.plain = plain
+
+	// messages and tables are colored with ansi.Color before they reach the logger,
+	// so colors have to be disabled globally
+	ansi.DisableColors(plain)
+}
+
+func (s *stdoutLogger) GetVerbosity() int {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	return s.verbosity
+}
+
 func (s *stdoutLogger) Write(message []byte) (int, error) {
 	s.logMutex.Lock()
 	defer s.logMutex.Unlock()
@@ -400,7 +456,7 @@ func (s *stdoutLogger) Write(message []byte) (int, error) {
 			s.loadingText.Stop()
 		}
 
-		n, err := fnTypeInformationMap[infoFn].stream.Write(message)
+		n, err := s.stream(infoFn).Write(message)
 
 		if s.loadingText != nil {
 			s.loadingText.Start()
@@ -421,7 +477,7 @@ func (s *stdoutLogger) WriteString(message string) {
 			s.loadingText.Stop()
 		}
 
-		fnTypeInformationMap[infoFn].stream.Write([]byte(message))
+		s.stream(infoFn).Write([]byte(message))
 
 		if s.loadingText != nil {
 			s.loadingText.Start()
@@ -13,8 +13,9 @@ import (
 
 // StreamLogger logs all messages to a stream
 type StreamLogger struct {
-	logMutex sync.Mutex
-	level    logrus.Level
+	logMutex  sync.Mutex
+	level     logrus.Level
+	verbosity int
 
 	stream io.Writer
 }
@@ -291,6 +292,25 @@ func (s *StreamLogger) GetLevel() logrus.Level {
 	return s.level
 }
 
+// SetVerbosity implements interface
+func (s *StreamLogger) SetVerbosity(verbosity int) {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	s.verbosity = verbosity
+}
+
+// GetVerbosity implements interface
+func (s *StreamLogger) GetVerbosity() int {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	return s.verbosity
+}
+
+// SetPlain implements interface, the stream logger never shows a spinner
+func (s *StreamLogger) SetPlain(plain bool) {}
+
 func (s *StreamLogger) Write(message []byte) (int, error) {
 	s.logMutex.Lock()
 	defer s.logMutex.Unlock()
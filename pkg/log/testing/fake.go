@@ -94,6 +94,15 @@ func (d *FakeLogger) SetLevel(level logrus.Level) {}
 // GetLevel implements logger interface
 func (d *FakeLogger) GetLevel() logrus.Level { return logrus.FatalLevel }
 
+// SetVerbosity implements logger interface
+func (d *FakeLogger) SetVerbosity(verbosity int) {}
+
+// GetVerbosity implements logger interface
+func (d *FakeLogger) GetVerbosity() int { return 0 }
+
+// SetPlain implements logger interface
+func (d *FakeLogger) SetPlain(plain bool) {}
+
 // Write implements logger interface
 func (d *FakeLogger) Write(message []byte) (int, error) {
 	return len(message), nil
@@ -3,12 +3,46 @@ package printhelper
 import (
 	"github.com/loft-sh/loftctl/pkg/log"
 	"github.com/mgutz/ansi"
+	"net"
 )
 
+// NoBanner disables the decorative banners around the printed messages
+var NoBanner = false
+
+const (
+	dnsHeader   = "###################################     DNS CONFIGURATION REQUIRED     ##################################"
+	dnsFooter   = "#########################################################################################################"
+	loginHeader = "##########################   LOGIN   ############################"
+	loginFooter = "#################################################################"
+)
+
+// PrintDNSConfigurationForAddress prints the DNS record that has to be created for the given external
+// address of the ingress controller load balancer
+func PrintDNSConfigurationForAddress(host, address string, log log.Logger) {
+	record := "an A-record"
+	if net.ParseIP(address) == nil {
+		record = "a CNAME-record"
+	}
+
+	log.WriteString(`
+
+` + banner(dnsHeader, "DNS configuration required:") + `
+
+Create ` + record + ` for ` + host + ` pointing to ` + address + `, which is the
+external address of your nginx-ingress controller.
+
+` + banner(dnsFooter, "") + `
+
+The command will wait until loft is reachable under the host. You can also abort and use port-forwarding instead
+by running 'loft start' again.
+
+`)
+}
+
 func PrintDNSConfiguration(host string, log log.Logger) {
 	log.WriteString(`
 
-###################################     DNS CONFIGURATION REQUIRED     ##################################
+` + banner(dnsHeader, "DNS configuration required:") + `
 
 Create a DNS A-record for ` + host + ` with the EXTERNAL-IP of your nginx-ingress controller.
 To find this EXTERNAL-IP, run the following command and look at the output:
@@ -21,7 +55,7 @@ ingress-nginx-controller   LoadBalancer   10.0.0.244 | XX.XXX.XXX.XX |  80:30984
 
 EXTERNAL-IP may be 'pending' for a while until your cloud provider has created a new load balancer.
 
-#########################################################################################################
+` + banner(dnsFooter, "") + `
 
 The command will wait until loft is reachable under the host. You can also abort and use port-forwarding instead
 by running 'loft start' again.
@@ -29,12 +63,12 @@ by running 'loft start' again.
 `)
 }
 
-func PrintSuccessMessageLocalInstall(password, localPort string, log log.Logger) {
+func PrintSuccessMessageLocalInstall(password, localPort, loftVersion string, log log.Logger) {
 	url := "https://localhost:" + localPort
 	log.WriteString(`
 
-##########################   LOGIN   ############################
-
+` + banner(loginHeader, "Login:") + `
+` + versionLine(loftVersion) + `
 Username: ` + ansi.Color("admin", "green+b") + `
 Password: ` + ansi.Color(password, "green+b") + `
 
@@ -43,7 +77,7 @@ Login via CLI: ` + ansi.Color(`loft login --insecure `+url, "green+b") + `
 
 !!! You must accept the untrusted certificate in your browser !!!
 
-#################################################################
+` + banner(loginFooter, "") + `
 
 Loft was successfully installed and port-forwarding has been started.
 If you stop this command, run 'loft start' again to restart port-forwarding.
@@ -52,13 +86,13 @@ Thanks for using loft!
 `)
 }
 
-func PrintSuccessMessageRemoteInstall(host, password string, log log.Logger) {
+func PrintSuccessMessageRemoteInstall(host, password, loftVersion string, log log.Logger) {
 	url := "https://" + host
 	log.WriteString(`
 
 
-##########################   LOGIN   ############################
-
+` + banner(loginHeader, "Login:") + `
+` + versionLine(loftVersion) + `
 Username: ` + ansi.Color("admin", "green+b") + `
 Password: ` + ansi.Color(password, "green+b") + `
 
@@ -69,10 +103,27 @@ Login via CLI: ` + ansi.Color(`loft login --insecure `+url, "green+b") + `
 
 Follow this guide to add a valid certificate: https://loft.sh/docs/administration/ssl
 
-#################################################################
+` + banner(loginFooter, "") + `
 
 Loft was successfully installed and can now be reached at: ` + url + `
 
 Thanks for using loft!
 `)
 }
+
+func versionLine(loftVersion string) string {
+	if loftVersion == "" {
+		return ""
+	}
+
+	return "\nVersion:  " + ansi.Color(loftVersion, "green+b") + "\n"
+}
+
+// banner returns the decorated banner line or the plain text if banners are disabled
+func banner(decorated, plain string) string {
+	if NoBanner {
+		return plain
+	}
+
+	return decorated
+}
@@ -3,10 +3,17 @@ package upgrade
 import (
 	"fmt"
 	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
 	"k8s.io/klog"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/loft-sh/loftctl/pkg/log"
@@ -53,6 +60,46 @@ func GetVersion() string {
 	return version
 }
 
+// UserAgent returns the user agent loftctl sends with its requests, so they can be told apart
+// in the loft and kubernetes audit logs
+func UserAgent() string {
+	if version == "" {
+		return "loftctl/dev"
+	}
+
+	return "loftctl/" + version
+}
+
+// build metadata that is passed in from main via SetBuildInfo
+var commit string
+var buildDate string
+
+// BuildInfo describes the build of the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// SetBuildInfo sets the git commit and the build date of the binary
+func SetBuildInfo(gitCommit, date string) {
+	commit = gitCommit
+	buildDate = date
+}
+
+// GetBuildInfo returns the version and build metadata of the binary
+func GetBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:   rawVersion,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
 // SetVersion sets the application version
 func SetVersion(verText string) {
 	if len(verText) > 0 {
@@ -73,26 +120,72 @@ var (
 	latestVersionOnce sync.Once
 )
 
+// cacheDir is the directory the result of the version check is cached in, empty disables the cache
+var cacheDir string
+
+// latestVersionCacheFile is the file in the cache dir that holds the latest version found on github
+const latestVersionCacheFile = "latest-version"
+
+// LatestVersionCacheDuration is how long the latest version found on github is cached
+const LatestVersionCacheDuration = time.Hour * 24
+
+// SetCacheDir sets the directory the result of the version check is cached in
+func SetCacheDir(dir string) {
+	cacheDir = dir
+}
+
 // CheckForNewerVersion checks if there is a newer version on github and returns the newer version
 func CheckForNewerVersion() (string, error) {
 	latestVersionOnce.Do(func() {
-		latest, found, err := selfupdate.DetectLatest(githubSlug)
+		latest, err := getLatestVersion()
 		if err != nil {
 			latestVersionErr = err
 			return
 		}
 
 		v := semver.MustParse(version)
-		if !found || latest.Version.Equals(v) {
+		if latest == nil || latest.Equals(v) {
 			return
 		}
 
-		latestVersion = latest.Version.String()
+		latestVersion = latest.String()
 	})
 
 	return latestVersion, latestVersionErr
 }
 
+// getLatestVersion returns the latest version from the cache or, if the cache is empty or expired, from github
+func getLatestVersion() (*semver.Version, error) {
+	cacheFile := ""
+	if cacheDir != "" {
+		cacheFile = filepath.Join(cacheDir, latestVersionCacheFile)
+		stat, err := os.Stat(cacheFile)
+		if err == nil && time.Since(stat.ModTime()) < LatestVersionCacheDuration {
+			out, err := ioutil.ReadFile(cacheFile)
+			if err == nil {
+				cached, err := semver.Parse(strings.TrimSpace(string(out)))
+				if err == nil {
+					return &cached, nil
+				}
+			}
+		}
+	}
+
+	latest, found, err := selfupdate.DetectLatest(githubSlug)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, nil
+	}
+
+	// the cache is only an optimization, so errors are ignored
+	if cacheFile != "" && os.MkdirAll(cacheDir, 0755) == nil {
+		_ = ioutil.WriteFile(cacheFile, []byte(latest.Version.String()), 0644)
+	}
+
+	return &latest.Version, nil
+}
+
 // NewerVersionAvailable checks if there is a newer version of loft
 func NewerVersionAvailable() string {
 	// Get version of current binary
@@ -170,3 +263,65 @@ func Upgrade(flagVersion string, log log.Logger) error {
 
 	return nil
 }
+
+// Download downloads the given or latest release from github into the given directory without
+// replacing the current binary and returns the path of the downloaded binary
+func Download(flagVersion, dir string, log log.Logger) (string, error) {
+	var (
+		release *selfupdate.Release
+		found   bool
+		err     error
+	)
+	if flagVersion != "" {
+		release, found, err = selfupdate.DetectVersion(githubSlug, flagVersion)
+	} else {
+		release, found, err = selfupdate.DetectLatest(githubSlug)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "find version")
+	} else if !found {
+		return "", fmt.Errorf("loft version %s couldn't be found", flagVersion)
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	log.StartWait(fmt.Sprintf("Downloading version %s...", release.Version))
+	defer log.StopWait()
+
+	resp, err := http.Get(release.AssetURL)
+	if err != nil {
+		return "", errors.Wrap(err, "download release")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download release: unexpected status code %d", resp.StatusCode)
+	}
+
+	binary, err := selfupdate.UncompressCommand(resp.Body, release.AssetURL, "loft")
+	if err != nil {
+		return "", errors.Wrap(err, "uncompress release")
+	}
+
+	binaryName := "loft"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+
+	binaryPath := filepath.Join(dir, binaryName)
+	out, err := os.OpenFile(binaryPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, binary)
+	if err != nil {
+		return "", errors.Wrap(err, "write binary")
+	}
+
+	return binaryPath, nil
+}
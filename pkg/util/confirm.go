@@ -0,0 +1,41 @@
+package util
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/loft-sh/loftctl/pkg/survey"
+)
+
+// EnvAssumeYes skips the confirmation of destructive operations if set to true, e.g. in automation
+const EnvAssumeYes = "LOFT_ASSUME_YES"
+
+// ConfirmDestructive asks the user to confirm a destructive operation and returns if it was confirmed.
+// The question is skipped if assumeYes is true or LOFT_ASSUME_YES is set to true
+func ConfirmDestructive(question string, assumeYes bool, log log.Logger) (bool, error) {
+	if assumeYes {
+		return true, nil
+	} else if envAssumeYes, _ := strconv.ParseBool(os.Getenv(EnvAssumeYes)); envAssumeYes {
+		return true, nil
+	}
+
+	const (
+		YesOption = "Yes"
+		NoOption  = "No"
+	)
+
+	answer, err := log.Question(&survey.QuestionOptions{
+		Question:     question,
+		DefaultValue: NoOption,
+		Options: []string{
+			NoOption,
+			YesOption,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return answer == YesOption, nil
+}
@@ -0,0 +1,55 @@
+package util
+
+import "strings"
+
+// ClosestMatch returns the candidate with the smallest case insensitive levenshtein distance
+// to the given value, or an empty string if no candidate is closer than maxDistance
+func ClosestMatch(value string, candidates []string, maxDistance int) string {
+	closest := ""
+	closestDistance := maxDistance
+	for _, candidate := range candidates {
+		distance := LevenshteinDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+
+	return closest
+}
+
+// LevenshteinDistance returns the number of single character edits needed to turn a into b
+func LevenshteinDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"github.com/loft-sh/loftctl/pkg/log"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"time"
+)
+
+// WaitForCondition polls the given condition function every interval until it returns true, an error or
+// the timeout is reached. While waiting a spinner with the given message is shown, e.g.
+// "loft to become reachable", which is also used to build a consistent timeout error.
+func WaitForCondition(ctx context.Context, interval, timeout time.Duration, msg string, log log.Logger, fn wait.ConditionFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	log.StartWait("Waiting for " + msg + "...")
+	defer log.StopWait()
+
+	err := wait.PollImmediateUntil(interval, fn, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out after %s waiting for %s", timeout.String(), msg)
+	} else if err != nil {
+		return errors.Wrapf(err, "wait for %s", msg)
+	}
+
+	return nil
+}
+
+// BackoffConditionFunc returns if the condition is met and if the poll interval should be reset,
+// e.g. because the state that is waited on has changed and will probably change again soon
+type BackoffConditionFunc func() (done bool, reset bool, err error)
+
+// WaitForConditionWithBackoff works like WaitForCondition, but doubles the interval after each
+// unsuccessful poll up to maxInterval and adds some jitter, which keeps the number of requests
+// low during long waits. The interval starts over whenever the condition function asks for it.
+func WaitForConditionWithBackoff(ctx context.Context, interval, maxInterval, timeout time.Duration, msg string, log log.Logger, fn BackoffConditionFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	log.StartWait("Waiting for " + msg + "...")
+	defer log.StopWait()
+
+	initialInterval := interval
+	for {
+		done, reset, err := fn()
+		if err != nil {
+			return errors.Wrapf(err, "wait for %s", msg)
+		} else if done {
+			return nil
+		} else if reset {
+			interval = initialInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for %s", timeout.String(), msg)
+		case <-time.After(wait.Jitter(interval, 0.2)):
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}